@@ -8,12 +8,12 @@ import HelpModal from '../components/HelpModal';
 import { useTheme } from '../hooks/useTheme';
 import type { ValidationResult } from '../types/validation';
 
-// Função que faz a chamada POST /api/validar
+// Função que faz a chamada POST /api/v1/validar
 async function validarArquivo(file: File): Promise<ValidationResult> {
   const formData = new FormData();
   formData.append('file', file);
 
-  const response = await fetch('/api/validar', {
+  const response = await fetch('/api/v1/validar', {
     method: 'POST',
     body: formData,
   });
@@ -1,9 +1,56 @@
+export type Severity = 'erro' | 'aviso' | 'info';
+
+export interface NcmSuggestion {
+  ncm: string;
+  descricao: string;
+  pontuacao: number;
+}
+
 export interface ValidationError {
   linha: number;
   coluna: string;
   nomeColuna: string;
-  tipo: 'VAZIA' | 'NCM' | 'CSOSN' | 'CST_ORIGEM' | 'TIPO_ITEM';
+  tipo: 'VAZIA' | 'NCM' | 'CSOSN' | 'CST_ORIGEM' | 'TIPO_ITEM' | 'FCI' | 'CBENEF' | 'UNIDADE' | 'SERVICO' | 'ANP' | 'IBS_CBS' | 'TEXTO' | 'CELULA_NUMERICA' | 'EFD' | 'CONSISTENCIA';
+  codigo: string;
+  severidade: Severity;
+  chave?: string;
   mensagem: string;
+  sugestoes?: NcmSuggestion[];
+  valorSugerido?: string;
+}
+
+export interface ValueFrequency {
+  valor: string;
+  ocorrencias: number;
+}
+
+export interface ColumnProfile {
+  coluna: string;
+  nomeColuna: string;
+  preenchidas: number;
+  taxaPreenchimento: number;
+  valoresDistintos: number;
+  maisFrequentes: ValueFrequency[];
+  tamanhoMinimo: number;
+  tamanhoMaximo: number;
+  tipoDetectado: 'vazia' | 'numerico' | 'codigo' | 'texto' | 'data';
+}
+
+export interface ColumnCoverage {
+  coluna: string;
+  nomeColuna: string;
+  obrigatoriedade: 'obrigatoria' | 'condicional';
+  exigidas: number;
+  preenchidas: number;
+  percentual: number;
+}
+
+export interface DuplicateGroup {
+  criterio: 'CODIGO' | 'DESCRICAO';
+  chave: string;
+  linhas: number[];
+  divergente: boolean;
+  camposDivergentes: string[];
 }
 
 export interface ValidationResult {
@@ -15,7 +62,63 @@ export interface ValidationResult {
   errosCSTOrigem: number;
   errosCSOSN: number;
   errosTipoItem: number;
+  errosPorTipo: Record<string, number>;
+  porSeveridade: Record<Severity, number>;
   detalhes: ValidationError[];
+  perfil: ColumnProfile[];
+  cobertura: ColumnCoverage[];
+  coberturaObrigatorias: number;
+  duplicados: DuplicateGroup[];
+  totalAvisos: number;
+  avisos: ValidationError[];
+  totalIgnorados: number;
+  ignorados: ValidationError[];
 }
 
 export type ErrorFilter = 'all' | 'VAZIA' | 'NCM' | 'CSOSN' | 'CST_ORIGEM' | 'TIPO_ITEM';
+
+export interface ComparisonResult {
+  arquivoAnterior: string;
+  arquivoAtual: string;
+  totalCorrigidos: number;
+  totalNovos: number;
+  totalPersistentes: number;
+  corrigidos: ValidationError[];
+  novos: ValidationError[];
+  persistentes: ValidationError[];
+}
+
+export interface NfeItem {
+  arquivoXML: string;
+  chaveNFe: string;
+  numeroItem: number;
+  codigo: string;
+  gtin: string;
+  descricao: string;
+  ncm: string;
+  cest: string;
+  origem: string;
+  cst: string;
+  csosn: string;
+}
+
+export interface NfeDivergence {
+  linha: number;
+  chave: string;
+  campo: string;
+  valorPlanilha: string;
+  valorNFe: string;
+  casadoPor: 'CODIGO' | 'GTIN';
+  item: NfeItem;
+}
+
+export interface NfeCrossCheckResult {
+  nomeArquivo: string;
+  totalXMLs: number;
+  totalItens: number;
+  totalConferidos: number;
+  totalDivergencias: number;
+  divergencias: NfeDivergence[];
+  semCorrespondencia: NfeItem[];
+  xmlsIgnorados: string[];
+}
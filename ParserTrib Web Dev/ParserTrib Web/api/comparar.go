@@ -0,0 +1,117 @@
+package api
+
+import (
+	"ParserTrib/internal/comparacao"
+	"ParserTrib/internal/domain"
+	"ParserTrib/internal/excel"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Comparar é o endpoint POST /api/comparar
+// Recebe duas versões via multipart/form-data (campos "anterior" e "atual", cada um .xlsx ou o .json
+// de um resultado salvo) e devolve os problemas corrigidos, novos e persistentes. O campo opcional
+// "colunaChave" define a coluna que identifica as linhas nas planilhas (padrão: a da configuração).
+func (h *Handler) Comparar(c *gin.Context) {
+	tmpDir, err := os.MkdirTemp("", "parsertrib-comparar-*")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.RespostaErroAPI{
+			Erro: "Erro ao criar diretório temporário",
+		})
+		return
+	}
+	defer os.RemoveAll(tmpDir)
+
+	uf, err := h.ufDaRequisicao(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.RespostaErroAPI{
+			Erro: err.Error(),
+		})
+		return
+	}
+
+	opcoes := h.opcoesValidacao(uf)
+	if coluna := strings.TrimSpace(c.PostForm("colunaChave")); coluna != "" {
+		opcoes.ColunaChave = coluna
+	}
+
+	inicio := time.Now()
+	var versoes [2]domain.RespostaValidacaoAPI
+	var caminho string
+	for i, campo := range []string{"anterior", "atual"} {
+		caminho, err = salvarUpload(c, campo, filepath.Join(tmpDir, campo), ".xlsx", ".json")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, domain.RespostaErroAPI{
+				Erro:     fmt.Sprintf("Arquivo '%s' não fornecido ou inválido", campo),
+				Detalhes: err.Error(),
+			})
+			return
+		}
+
+		versoes[i], err = h.carregarVersao(caminho, opcoes)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, domain.RespostaErroAPI{
+				Erro:     fmt.Sprintf("Erro ao processar o arquivo '%s'", campo),
+				Detalhes: err.Error(),
+			})
+			return
+		}
+	}
+
+	comparacaoVersoes := comparacao.Comparar(versoes[0], versoes[1])
+	h.registrarOperacao(c, operacaoComparar, caminho, time.Since(inicio))
+
+	c.JSON(http.StatusOK, comparacaoVersoes)
+}
+
+// carregarVersao obtém um dos lados da comparação: planilhas são validadas, resultados salvos são
+// apenas lidos
+func (h *Handler) carregarVersao(caminho string, opcoes excel.OpcoesValidacao) (domain.RespostaValidacaoAPI, error) {
+	if strings.ToLower(filepath.Ext(caminho)) != ".xlsx" {
+		return comparacao.Carregar(caminho, h.cfg.SheetPadrao, opcoes, h.baseline)
+	}
+
+	resultado, err := h.validarArquivo(caminho, opcoes)
+	if err != nil {
+		return domain.RespostaValidacaoAPI{}, err
+	}
+	return resultado.ToRespostaAPI(), nil
+}
+
+// salvarUpload grava o arquivo do campo informado em dir, mantendo o nome original (usado como
+// identidade do arquivo no baseline). Aceita apenas as extensões informadas.
+func salvarUpload(c *gin.Context, campo, dir string, extensoes ...string) (string, error) {
+	arquivo, header, err := c.Request.FormFile(campo)
+	if err != nil {
+		return "", err
+	}
+	defer arquivo.Close()
+
+	nome := filepath.Base(header.Filename)
+	if !slices.Contains(extensoes, strings.ToLower(filepath.Ext(nome))) {
+		return "", fmt.Errorf("apenas arquivos %s são aceitos (recebido: '%s')", strings.Join(extensoes, " ou "), nome)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	caminho := filepath.Join(dir, nome)
+	destino, err := os.Create(caminho)
+	if err != nil {
+		return "", err
+	}
+	defer destino.Close()
+
+	if _, err := io.Copy(destino, arquivo); err != nil {
+		return "", err
+	}
+	return caminho, nil
+}
@@ -1,37 +1,212 @@
 package api
 
 import (
+	"ParserTrib/internal/baseline"
+	"ParserTrib/internal/cbenef"
 	"ParserTrib/internal/config"
+	"ParserTrib/internal/domain"
 	"ParserTrib/internal/excel"
+	"ParserTrib/internal/metricas"
+	"ParserTrib/internal/ncm"
+	"ParserTrib/internal/reforma"
+	"ParserTrib/internal/sped"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// Limites do endpoint de validação de itens em JSON
+const (
+	maximoItensPorRequisicao = 1000
+	tamanhoMaximoCorpoJSON   = 2 << 20 // 2MB
+)
+
+// tamanhoMaximoCorpoNFe limita o corpo de POST /api/nfe (planilha e todos os XMLs ou .zip enviados)
+const tamanhoMaximoCorpoNFe = 100 << 20 // 100MB
+
+// Operações registradas em parsertrib_operacoes_total
+const (
+	operacaoNFe      = "nfe"
+	operacaoComparar = "comparar"
+	operacaoSeparar  = "separar"
+)
+
+// tipoConteudoXLSX é o MIME type das planilhas devolvidas pela API
+const tipoConteudoXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
 // Handler encapsula as dependências necessárias para os endpoints
 type Handler struct {
-	cfg *config.Config
+	cfg        *config.Config
+	log        *slog.Logger
+	indiceNCM  *ncm.Indice // nil quando a tabela NCM não está disponível
+	perfil     *excel.PerfilRegras
+	baseline   *domain.Baseline
+	beneficios *cbenef.Tabela // nil quando a tabela cBenef não está disponível
+	unidades   []excel.CodigoTabela
+	servicos   []excel.CodigoTabela
+	anp        []excel.CodigoTabela
+	reforma    *reforma.Tabela
 }
 
-// NovoHandler cria uma instância do handler com as configurações
-func NovoHandler(cfg *config.Config) *Handler {
-	return &Handler{cfg: cfg}
+// regexUF aceita a sigla da UF informada na requisição
+var regexUF = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// NovoHandler cria uma instância do handler com as configurações e o logger estruturado.
+// A tabela NCM, o perfil de regras e o baseline são carregados uma única vez aqui; sem a tabela a API
+// funciona sem sugestões de NCM, sem perfil valem as severidades padrão e sem baseline nada é ignorado.
+// A tabela cBenef também é opcional: sem ela a regra do cBenef não é aplicada; sem tabela de unidades
+// vale a tabela padrão; sem a lista da LC 116 ou a tabela da ANP, só o formato dos códigos é validado.
+// A tabela IBS/CBS só é lida quando o perfil habilita as regras da reforma.
+func NovoHandler(cfg *config.Config, log *slog.Logger) *Handler {
+	h := &Handler{cfg: cfg, log: log}
+
+	indice, err := ncm.CarregarCSV(cfg.TabelaNCM)
+	if err != nil {
+		log.Warn("sugestões de NCM desativadas", slog.String("erro", err.Error()))
+	} else {
+		h.indiceNCM = indice
+		log.Info("tabela NCM carregada", slog.Int("codigos", indice.Total()))
+	}
+
+	perfil, err := excel.CarregarPerfilRegras(cfg.PerfilRegras)
+	if err != nil {
+		log.Error("perfil de regras ignorado, usando severidades padrão", slog.String("erro", err.Error()))
+		perfil = &excel.PerfilRegras{}
+	}
+	h.perfil = perfil
+
+	supressoes, err := baseline.Carregar(cfg.Baseline)
+	if err != nil {
+		log.Error("baseline ignorado", slog.String("erro", err.Error()))
+		supressoes = &domain.Baseline{}
+	}
+	h.baseline = supressoes
+
+	beneficios, err := cbenef.CarregarCSV(cfg.TabelaCBenef)
+	if err != nil {
+		log.Warn("regra do cBenef desativada", slog.String("erro", err.Error()))
+	} else {
+		h.beneficios = beneficios
+		log.Info("tabela cBenef carregada", slog.Int("codigos", beneficios.Total()), slog.String("ufs", strings.Join(beneficios.UFs(), ",")))
+	}
+
+	unidades, err := excel.CarregarTabelaUnidades(cfg.TabelaUnidade)
+	if err != nil {
+		log.Error("tabela de unidades ignorada, usando a tabela padrão", slog.String("erro", err.Error()))
+		unidades = excel.TabelaUnidades
+	}
+	h.unidades = unidades
+
+	servicos, err := excel.CarregarTabelaServicos(cfg.TabelaServico)
+	if err != nil {
+		log.Warn("lista de serviços da LC 116 indisponível, validando só o formato", slog.String("erro", err.Error()))
+	} else {
+		h.servicos = servicos
+		log.Info("lista de serviços da LC 116 carregada", slog.Int("itens", len(servicos)))
+	}
+
+	produtosANP, err := excel.CarregarTabelaANP(cfg.TabelaANP)
+	if err != nil {
+		log.Warn("tabela de produtos da ANP indisponível, validando só o formato", slog.String("erro", err.Error()))
+	} else {
+		h.anp = produtosANP
+		log.Info("tabela de produtos da ANP carregada", slog.Int("codigos", len(produtosANP)))
+	}
+
+	if perfil.ReformaHabilitada() {
+		tabela, err := reforma.Carregar(cfg.TabelaReforma)
+		if err != nil {
+			log.Error("regras IBS/CBS habilitadas no perfil, mas desativadas por falta da tabela", slog.String("erro", err.Error()))
+		} else {
+			h.reforma = tabela
+			csts, classTribs := tabela.Total()
+			log.Info("tabela IBS/CBS carregada", slog.String("versao", tabela.Versao), slog.Int("csts", csts), slog.Int("cClassTrib", classTribs))
+		}
+	}
+
+	return h
+}
+
+// ufDaRequisicao devolve a UF do parâmetro "uf" (formulário ou query string) ou, sem ele, a da configuração
+func (h *Handler) ufDaRequisicao(c *gin.Context) (string, error) {
+	uf := strings.ToUpper(strings.TrimSpace(c.Request.FormValue("uf")))
+	if uf == "" {
+		return h.cfg.UF, nil
+	}
+	if !regexUF.MatchString(uf) {
+		return "", fmt.Errorf("UF inválida: '%s' (use a sigla com duas letras, ex.: PR)", uf)
+	}
+	return uf, nil
+}
+
+// opcoesValidacao reúne as dependências carregadas no handler para validar um arquivo
+func (h *Handler) opcoesValidacao(uf string) excel.OpcoesValidacao {
+	return excel.OpcoesValidacao{
+		IndiceNCM:   h.indiceNCM,
+		Perfil:      h.perfil,
+		ColunaChave: h.cfg.ColunaChave,
+		Beneficios:  h.beneficios,
+		UF:          uf,
+		Unidades:    h.unidades,
+		Servicos:    h.servicos,
+		Reforma:     h.reforma,
+
+		PrefixosCombustivel: h.cfg.PrefixosCombustivel,
+		ANP:                 h.anp,
+	}
+}
+
+// RegistrarRotas registra os endpoints da API no grupo informado (ex.: /api e /api/v1)
+func (h *Handler) RegistrarRotas(grupo *gin.RouterGroup) {
+	grupo.POST("/validar", h.ValidarExcel)
+	grupo.POST("/validar/linhas", h.ValidarLinhas)
+	grupo.POST("/comparar", h.Comparar)
+	grupo.POST("/separar", h.Separar)
+	grupo.POST("/nfe", h.ConferirNFe)
+	grupo.GET("/modelo", h.BaixarModelo)
+	grupo.GET("/health", h.Health)
+}
+
+// RegistrarRotasRaiz registra os endpoints que ficam fora do versionamento (especificação e métricas)
+func (h *Handler) RegistrarRotasRaiz(router *gin.Engine) {
+	router.GET("/api/openapi.json", h.OpenAPI)
+	router.GET("/metrics", h.Metricas)
+}
+
+// Metricas é o endpoint GET /metrics no formato texto do Prometheus
+func (h *Handler) Metricas(c *gin.Context) {
+	c.Header("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.Status(http.StatusOK)
+	metricas.Escrever(c.Writer)
+}
+
+// Health é o endpoint GET /api/health — útil pra confirmar que o servidor tá rodando
+func (h *Handler) Health(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
 }
 
 // ValidarExcel é o endpoint POST /api/validar
-// Recebe um arquivo .xlsx via multipart/form-data e retorna os erros de validação
+// Recebe um arquivo .xlsx via multipart/form-data e retorna os erros de validação. O campo opcional
+// "efd" (arquivo .txt do SPED EFD) concilia a planilha com os registros 0200 (categoria EFD)
 func (h *Handler) ValidarExcel(c *gin.Context) {
 	// 1. Receber o arquivo do upload
 	arquivo, header, err := c.Request.FormFile("file")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"erro":    "Arquivo não fornecido ou erro no upload",
-			"detalhes": err.Error(),
+		c.JSON(http.StatusBadRequest, domain.RespostaErroAPI{
+			Erro:     "Arquivo não fornecido ou erro no upload",
+			Detalhes: err.Error(),
 		})
 		return
 	}
@@ -40,8 +215,8 @@ func (h *Handler) ValidarExcel(c *gin.Context) {
 	// 2. Validar extensão
 	nomeArquivo := header.Filename
 	if !strings.HasSuffix(strings.ToLower(nomeArquivo), ".xlsx") {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"erro": "Apenas arquivos .xlsx são aceitos",
+		c.JSON(http.StatusBadRequest, domain.RespostaErroAPI{
+			Erro: "Apenas arquivos .xlsx são aceitos",
 		})
 		return
 	}
@@ -49,8 +224,8 @@ func (h *Handler) ValidarExcel(c *gin.Context) {
 	// 3. Salvar arquivo temporariamente
 	tmpDir, err := os.MkdirTemp("", "parsertrib-upload-*")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"erro": "Erro ao criar diretório temporário",
+		c.JSON(http.StatusInternalServerError, domain.RespostaErroAPI{
+			Erro: "Erro ao criar diretório temporário",
 		})
 		return
 	}
@@ -59,20 +234,22 @@ func (h *Handler) ValidarExcel(c *gin.Context) {
 	caminhoTmp := filepath.Join(tmpDir, nomeArquivo)
 	arquivoTmp, err := os.Create(caminhoTmp)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"erro": "Erro ao criar arquivo temporário",
+		c.JSON(http.StatusInternalServerError, domain.RespostaErroAPI{
+			Erro: "Erro ao criar arquivo temporário",
 		})
 		return
 	}
 
+	hash := sha256.New()
 	buf := make([]byte, 1024*1024) // 1MB por vez
 	for {
 		n, readErr := arquivo.Read(buf)
 		if n > 0 {
+			hash.Write(buf[:n])
 			if _, writeErr := arquivoTmp.Write(buf[:n]); writeErr != nil {
 				arquivoTmp.Close()
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"erro": "Erro ao salvar arquivo temporário",
+				c.JSON(http.StatusInternalServerError, domain.RespostaErroAPI{
+					Erro: "Erro ao salvar arquivo temporário",
 				})
 				return
 			}
@@ -83,12 +260,15 @@ func (h *Handler) ValidarExcel(c *gin.Context) {
 	}
 	arquivoTmp.Close()
 
+	hashArquivo := hex.EncodeToString(hash.Sum(nil))
+	c.Set(chaveHashArquivo, hashArquivo)
+
 	// 4. Abrir com o Reader existente
 	reader, err := excel.NovoReader(caminhoTmp, h.cfg.SheetPadrao)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"erro":    fmt.Sprintf("Erro ao abrir arquivo: %v", err),
-			"detalhes": "Verifique se o arquivo é um .xlsx válido com a aba '" + h.cfg.SheetPadrao + "'",
+		c.JSON(http.StatusBadRequest, domain.RespostaErroAPI{
+			Erro:     fmt.Sprintf("Erro ao abrir arquivo: %v", err),
+			Detalhes: "Verifique se o arquivo é um .xlsx válido com a aba '" + h.cfg.SheetPadrao + "'",
 		})
 		return
 	}
@@ -97,8 +277,8 @@ func (h *Handler) ValidarExcel(c *gin.Context) {
 	// 5. Obter metadados
 	planilha, err := reader.ObterMetadados()
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"erro": fmt.Sprintf("Erro ao ler metadados: %v", err),
+		c.JSON(http.StatusBadRequest, domain.RespostaErroAPI{
+			Erro: fmt.Sprintf("Erro ao ler metadados: %v", err),
 		})
 		return
 	}
@@ -106,19 +286,256 @@ func (h *Handler) ValidarExcel(c *gin.Context) {
 	// 6. Obter linhas e validar
 	rows, err := reader.ObterTodasLinhas()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"erro": fmt.Sprintf("Erro ao ler dados: %v", err),
+		c.JSON(http.StatusInternalServerError, domain.RespostaErroAPI{
+			Erro: fmt.Sprintf("Erro ao ler dados: %v", err),
+		})
+		return
+	}
+
+	metadados, err := reader.ObterMetadadosCelulas(rows)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.RespostaErroAPI{
+			Erro: fmt.Sprintf("Erro ao ler formato das células: %v", err),
 		})
 		return
 	}
 
+	uf, err := h.ufDaRequisicao(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.RespostaErroAPI{
+			Erro: err.Error(),
+		})
+		return
+	}
+
+	// Campo opcional "efd": SPED EFD para conciliar com os registros 0200
+	var itensEFD []domain.Item0200
+	caminhoEFD, err := salvarUpload(c, "efd", filepath.Join(tmpDir, "efd"), ".txt")
+	if err != nil && !errors.Is(err, http.ErrMissingFile) {
+		c.JSON(http.StatusBadRequest, domain.RespostaErroAPI{
+			Erro:     "Arquivo EFD inválido",
+			Detalhes: err.Error(),
+		})
+		return
+	}
+	if err == nil {
+		if itensEFD, err = sped.LerEFD(caminhoEFD); err != nil {
+			c.JSON(http.StatusBadRequest, domain.RespostaErroAPI{
+				Erro:     "Erro ao ler arquivo EFD",
+				Detalhes: err.Error(),
+			})
+			return
+		}
+	}
+
 	inicio := time.Now()
-	validador := excel.NovoValidator(rows, h.cfg.SheetPadrao, planilha.Cabecalhos)
+	opcoes := h.opcoesValidacao(uf)
+	opcoes.ItensEFD = itensEFD
+	validador := excel.NovoValidatorComOpcoes(rows, h.cfg.SheetPadrao, planilha.Cabecalhos, opcoes).ComMetadados(metadados)
 	resultado := validador.ValidarTudo(planilha.TotalLinhas)
-	resultado.TempoExecucao = time.Since(inicio)
 	resultado.NomeArquivo = nomeArquivo
+	baseline.Aplicar(h.baseline, &resultado)
+	resultado.TempoExecucao = time.Since(inicio)
 
-	// 7. Converter para resposta da API e retornar
+	// 7. Converter para resposta da API, registrar métricas e retornar
 	resposta := resultado.ToRespostaAPI()
+	metricas.ArquivosValidados.Inc()
+	h.registrarValidacao(c, nomeArquivo, planilha.TotalLinhas, resultado.TempoExecucao, resposta.Detalhes)
 	c.JSON(http.StatusOK, resposta)
 }
+
+// ValidarLinhas é o endpoint POST /api/validar/linhas
+// Recebe um array JSON de itens (objetos chave/valor com os nomes lógicos das colunas, ex.: "NCM", "CSOSN")
+// e retorna os erros de cada item pelo seu índice no array — pensado para validação campo a campo no ERP
+func (h *Handler) ValidarLinhas(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, tamanhoMaximoCorpoJSON)
+
+	var brutos []map[string]any
+	decoder := json.NewDecoder(c.Request.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&brutos); err != nil {
+		c.JSON(http.StatusBadRequest, domain.RespostaErroAPI{
+			Erro:     "Corpo inválido: esperado um array JSON de objetos",
+			Detalhes: err.Error(),
+		})
+		return
+	}
+	if len(brutos) == 0 {
+		c.JSON(http.StatusBadRequest, domain.RespostaErroAPI{
+			Erro: "Nenhum item enviado",
+		})
+		return
+	}
+	if len(brutos) > maximoItensPorRequisicao {
+		c.JSON(http.StatusBadRequest, domain.RespostaErroAPI{
+			Erro: fmt.Sprintf("Máximo de %d itens por requisição (recebido: %d)", maximoItensPorRequisicao, len(brutos)),
+		})
+		return
+	}
+
+	itens := make([]map[string]string, len(brutos))
+	for i, bruto := range brutos {
+		item, err := normalizarItem(bruto)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, domain.RespostaErroAPI{
+				Erro:     fmt.Sprintf("Item %d inválido", i),
+				Detalhes: err.Error(),
+			})
+			return
+		}
+		itens[i] = item
+	}
+
+	uf, err := h.ufDaRequisicao(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.RespostaErroAPI{
+			Erro: err.Error(),
+		})
+		return
+	}
+	opcoes := h.opcoesValidacao(uf)
+
+	inicio := time.Now()
+	resposta := domain.RespostaValidacaoLinhasAPI{
+		TotalItens: len(itens),
+		Itens:      make([]domain.ResultadoItem, 0, len(itens)),
+	}
+	var detalhes []domain.ErroValidacao
+	for i, item := range itens {
+		resultado := excel.ValidarItem(item, h.cfg.SheetPadrao, opcoes)
+		itemValidado := resultado.ToResultadoItem(i)
+		resposta.Itens = append(resposta.Itens, itemValidado)
+		detalhes = append(detalhes, resultado.ToRespostaAPI().Detalhes...)
+
+		// Mesma contagem de /api/validar: só problemas com severidade erro
+		resposta.TotalErros += resultado.TotalErros()
+		if !itemValidado.Valido {
+			resposta.ItensComErro++
+		}
+	}
+	duracao := time.Since(inicio)
+
+	resposta.TempoExecucao = duracao.String()
+
+	h.registrarValidacao(c, "", len(itens), duracao, detalhes)
+	c.JSON(http.StatusOK, resposta)
+}
+
+// BaixarModelo é o endpoint GET /api/modelo
+// Retorna a planilha modelo (.xlsx) com listas suspensas, comentários e a aba oculta de tabelas
+func (h *Handler) BaixarModelo(c *gin.Context) {
+	modelo, err := excel.GerarModelo(h.cfg.SheetPadrao, h.perfil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.RespostaErroAPI{
+			Erro: err.Error(),
+		})
+		return
+	}
+	defer modelo.Close()
+
+	buf, err := modelo.WriteToBuffer()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.RespostaErroAPI{
+			Erro: fmt.Sprintf("Erro ao gerar planilha modelo: %v", err),
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="modelo_produtos.xlsx"`)
+	c.Data(http.StatusOK, tipoConteudoXLSX, buf.Bytes())
+}
+
+// normalizarItem converte os valores JSON de um item (string, número, null) para texto,
+// do mesmo jeito que viriam de uma célula da planilha
+func normalizarItem(bruto map[string]any) (map[string]string, error) {
+	item := make(map[string]string, len(bruto))
+	for campo, valor := range bruto {
+		switch v := valor.(type) {
+		case nil:
+			item[campo] = ""
+		case string:
+			item[campo] = v
+		case json.Number:
+			item[campo] = v.String()
+		default:
+			return nil, fmt.Errorf("campo '%s' deve ser texto ou número", campo)
+		}
+	}
+	return item, nil
+}
+
+// registrarValidacao alimenta as métricas de validação e grava o resumo no log estruturado
+func (h *Handler) registrarValidacao(c *gin.Context, nomeArquivo string, linhas int, duracao time.Duration, detalhes []domain.ErroValidacao) {
+	metricas.LinhasProcessadas.Add(float64(linhas))
+	metricas.DuracaoValidacao.Observar(duracao.Seconds())
+
+	errosPorTipo := make(map[string]int)
+	for _, erro := range detalhes {
+		errosPorTipo[erro.Tipo]++
+	}
+	for tipo, total := range errosPorTipo {
+		metricas.ErrosValidacao.Add(float64(total), tipo)
+	}
+
+	atributos := []any{
+		slog.String("id_requisicao", c.GetString(chaveIDRequisicao)),
+		slog.Int("linhas", linhas),
+		slog.Int("total_erros", len(detalhes)),
+		slog.Float64("duracao_ms", float64(duracao.Microseconds())/1000),
+	}
+	if nomeArquivo != "" {
+		atributos = append(atributos,
+			slog.String("arquivo", nomeArquivo),
+			slog.String("hash_arquivo", c.GetString(chaveHashArquivo)),
+		)
+	}
+	h.log.InfoContext(c.Request.Context(), "validação concluída", atributos...)
+}
+
+// validarArquivo valida uma planilha já salva no servidor e aplica o baseline, como em POST /api/validar.
+// Não alimenta as métricas de validação: quem chama registra a própria operação com registrarOperacao.
+func (h *Handler) validarArquivo(caminho string, opcoes excel.OpcoesValidacao) (domain.ResultadoValidacaoCompleto, error) {
+	resultado, err := excel.ValidarArquivo(caminho, h.cfg.SheetPadrao, opcoes)
+	if err != nil {
+		return resultado, err
+	}
+	baseline.Aplicar(h.baseline, &resultado)
+	return resultado, nil
+}
+
+// registrarOperacao guarda o hash da planilha no contexto (para o log da requisição), alimenta as
+// métricas da operação (nfe, comparar, separar) e grava o resumo no log estruturado
+func (h *Handler) registrarOperacao(c *gin.Context, operacao, caminho string, duracao time.Duration) {
+	hash, err := hashArquivo(caminho)
+	if err != nil {
+		h.log.WarnContext(c.Request.Context(), "erro ao calcular hash do arquivo", slog.String("erro", err.Error()))
+	} else {
+		c.Set(chaveHashArquivo, hash)
+	}
+
+	metricas.Operacoes.Inc(operacao)
+	metricas.DuracaoOperacao.Observar(duracao.Seconds(), operacao)
+
+	h.log.InfoContext(c.Request.Context(), "operação concluída",
+		slog.String("id_requisicao", c.GetString(chaveIDRequisicao)),
+		slog.String("operacao", operacao),
+		slog.String("arquivo", filepath.Base(caminho)),
+		slog.String("hash_arquivo", c.GetString(chaveHashArquivo)),
+		slog.Float64("duracao_ms", float64(duracao.Microseconds())/1000),
+	)
+}
+
+// hashArquivo calcula o SHA-256 (hexadecimal) do arquivo informado
+func hashArquivo(caminho string) (string, error) {
+	arquivo, err := os.Open(caminho)
+	if err != nil {
+		return "", err
+	}
+	defer arquivo.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, arquivo); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
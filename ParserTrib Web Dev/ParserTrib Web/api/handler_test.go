@@ -0,0 +1,113 @@
+package api
+
+import (
+	"ParserTrib/internal/config"
+	"ParserTrib/internal/domain"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// novoRouterTeste monta o router com as rotas da API, como em cmd/server.go
+func novoRouterTeste() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler := NovoHandler(config.Nova(), slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	handler.RegistrarRotas(router.Group("/api"))
+	handler.RegistrarRotasRaiz(router)
+	return router
+}
+
+// enviar executa a requisição no router e devolve a resposta gravada
+func enviar(router *gin.Engine, metodo, caminho, tipo string, corpo io.Reader) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(metodo, caminho, corpo)
+	req.Header.Set("Content-Type", tipo)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestValidarLinhasRequisicaoInvalida(t *testing.T) {
+	router := novoRouterTeste()
+
+	casos := []struct {
+		nome  string
+		corpo string
+		erro  string
+	}{
+		{nome: "lista vazia", corpo: "[]", erro: "Nenhum item enviado"},
+		{nome: "corpo que não é array", corpo: `{"NCM": "96081000"}`, erro: "Corpo inválido"},
+		{nome: "campo com objeto", corpo: `[{"NCM": "96081000"}, {"NCM": {"codigo": "96081000"}}]`, erro: "Item 1 inválido"},
+		{nome: "itens demais", corpo: "[" + strings.Repeat(`{"NCM": "96081000"},`, maximoItensPorRequisicao) + `{"NCM": "96081000"}]`, erro: "Máximo de 1000 itens"},
+		{nome: "corpo acima do limite", corpo: `[{"Descrição": "` + strings.Repeat("a", tamanhoMaximoCorpoJSON) + `"}]`, erro: "Corpo inválido"},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			w := enviar(router, http.MethodPost, "/api/validar/linhas", "application/json", strings.NewReader(caso.corpo))
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, esperado %d", w.Code, http.StatusBadRequest)
+			}
+			var resposta domain.RespostaErroAPI
+			if err := json.Unmarshal(w.Body.Bytes(), &resposta); err != nil {
+				t.Fatalf("resposta inválida: %v", err)
+			}
+			if !strings.Contains(resposta.Erro, caso.erro) {
+				t.Errorf("erro = %q, esperado conter %q", resposta.Erro, caso.erro)
+			}
+		})
+	}
+}
+
+func TestValidarLinhas(t *testing.T) {
+	router := novoRouterTeste()
+	corpo := `[{"NCM": "96081000"}, {"NCM": "123"}, {"NCM": 96081000, "Observação": null},
+		{"NCM": "96081000", "CST Origem": "0", "FCI": "B01F70AF-10BF-4B1F-848C-65FF57F616FE"}]`
+
+	w := enviar(router, http.MethodPost, "/api/validar/linhas", "application/json", strings.NewReader(corpo))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, esperado %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var resposta domain.RespostaValidacaoLinhasAPI
+	if err := json.Unmarshal(w.Body.Bytes(), &resposta); err != nil {
+		t.Fatalf("resposta inválida: %v", err)
+	}
+
+	if resposta.TotalItens != 4 || resposta.ItensComErro != 2 || len(resposta.Itens) != 4 {
+		t.Fatalf("totalItens/itensComErro/itens = %d/%d/%d, esperado 4/2/4", resposta.TotalItens, resposta.ItensComErro, len(resposta.Itens))
+	}
+	casos := []struct {
+		valido bool
+		campos []string
+	}{
+		{valido: true},
+		{campos: []string{"NCM"}},
+		{campos: []string{"Observação"}},        // null vira célula vazia
+		{valido: true, campos: []string{"FCI"}}, // aviso: não reprova o item nem entra no total
+	}
+	total := 0
+	for i, caso := range casos {
+		item := resposta.Itens[i]
+		if item.Indice != i || item.Valido != caso.valido || len(item.Erros) != len(caso.campos) {
+			t.Errorf("item %d = %+v, esperado índice %d, válido %v e %d erro(s)", i, item, i, caso.valido, len(caso.campos))
+			continue
+		}
+		for j, campo := range caso.campos {
+			if item.Erros[j].Campo != campo {
+				t.Errorf("item %d: erro no campo %q, esperado %q", i, item.Erros[j].Campo, campo)
+			}
+			if item.Erros[j].Severidade == domain.SeveridadeErro {
+				total++
+			}
+		}
+	}
+	if resposta.TotalErros != total || total != 2 {
+		t.Errorf("totalErros = %d (soma dos itens com severidade erro: %d), esperado 2", resposta.TotalErros, total)
+	}
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"ParserTrib/internal/config"
+	"ParserTrib/internal/metricas"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// valorMetrica devolve o valor da série informada (nome com labels) na saída de GET /metrics
+func valorMetrica(serie string) string {
+	var sb strings.Builder
+	metricas.Escrever(&sb)
+	for _, linha := range strings.Split(sb.String(), "\n") {
+		if valor, ok := strings.CutPrefix(linha, serie+" "); ok {
+			return valor
+		}
+	}
+	return ""
+}
+
+func TestRegistrarOperacao(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NovoHandler(config.Nova(), slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	caminho := filepath.Join(t.TempDir(), "produtos.xlsx")
+	if err := os.WriteFile(caminho, []byte("conteúdo"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	validadosAntes := valorMetrica("parsertrib_arquivos_validados_total")
+	duracaoAntes := valorMetrica("parsertrib_validacao_duracao_segundos_count")
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/nfe", nil)
+	h.registrarOperacao(c, operacaoNFe, caminho, 30*time.Millisecond)
+
+	if valor := valorMetrica(`parsertrib_operacoes_total{operacao="nfe"}`); valor != "1" {
+		t.Errorf("operações nfe = %q, esperado 1", valor)
+	}
+	if valor := valorMetrica(`parsertrib_operacao_duracao_segundos_bucket{operacao="nfe",le="0.05"}`); valor != "1" {
+		t.Errorf("bucket de 0.05s da operação nfe = %q, esperado 1", valor)
+	}
+	if valor := valorMetrica("parsertrib_arquivos_validados_total"); valor != validadosAntes {
+		t.Errorf("arquivos validados = %q, esperado %q: a operação não é validação", valor, validadosAntes)
+	}
+	if valor := valorMetrica("parsertrib_validacao_duracao_segundos_count"); valor != duracaoAntes {
+		t.Errorf("observações da duração de validação = %q, esperado %q", valor, duracaoAntes)
+	}
+	if c.GetString(chaveHashArquivo) == "" {
+		t.Error("hash do arquivo não foi guardado no contexto")
+	}
+}
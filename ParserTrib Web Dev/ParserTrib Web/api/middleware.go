@@ -0,0 +1,83 @@
+package api
+
+import (
+	"ParserTrib/internal/metricas"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Chaves usadas no contexto do Gin para compartilhar dados entre handler e middlewares
+const (
+	chaveIDRequisicao = "idRequisicao"
+	chaveHashArquivo  = "hashArquivo"
+)
+
+// cabecalhoIDRequisicao é o header HTTP que carrega o ID da requisição (aceito e devolvido)
+const cabecalhoIDRequisicao = "X-Request-ID"
+
+// IDRequisicao reaproveita o X-Request-ID enviado pelo cliente ou gera um novo,
+// e devolve o mesmo valor no header da resposta
+func IDRequisicao() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(cabecalhoIDRequisicao)
+		if id == "" || len(id) > 64 {
+			id = gerarIDRequisicao()
+		}
+		c.Set(chaveIDRequisicao, id)
+		c.Header(cabecalhoIDRequisicao, id)
+		c.Next()
+	}
+}
+
+// LogEstruturado registra cada requisição em JSON (log/slog) e alimenta as métricas HTTP
+func LogEstruturado(log *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		inicio := time.Now()
+		c.Next()
+		duracao := time.Since(inicio)
+
+		rota := c.FullPath()
+		if rota == "" {
+			rota = "desconhecida"
+		}
+		status := c.Writer.Status()
+
+		metricas.RequisicoesHTTP.Inc(c.Request.Method, rota, strconv.Itoa(status))
+		metricas.DuracaoHTTP.Observar(duracao.Seconds(), c.Request.Method, rota)
+
+		atributos := []any{
+			slog.String("id_requisicao", c.GetString(chaveIDRequisicao)),
+			slog.String("metodo", c.Request.Method),
+			slog.String("rota", rota),
+			slog.String("caminho", c.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Float64("duracao_ms", float64(duracao.Microseconds())/1000),
+			slog.String("ip", c.ClientIP()),
+		}
+		if hash := c.GetString(chaveHashArquivo); hash != "" {
+			atributos = append(atributos, slog.String("hash_arquivo", hash))
+		}
+
+		nivel := slog.LevelInfo
+		if status >= 500 {
+			nivel = slog.LevelError
+		} else if status >= 400 {
+			nivel = slog.LevelWarn
+		}
+		log.Log(c.Request.Context(), nivel, "requisição atendida", atributos...)
+	}
+}
+
+// gerarIDRequisicao gera 16 bytes aleatórios em hexadecimal
+func gerarIDRequisicao() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"ParserTrib/internal/domain"
+	"ParserTrib/internal/excel"
+	"ParserTrib/internal/nfe"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConferirNFe é o endpoint POST /api/nfe
+// Recebe a planilha (campo "file") e as notas (campo "xmls", repetível: arquivos .xml ou .zip com os
+// XMLs) via multipart/form-data e devolve as divergências entre as linhas e os itens das NF-e.
+func (h *Handler) ConferirNFe(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, tamanhoMaximoCorpoNFe)
+
+	tmpDir, err := os.MkdirTemp("", "parsertrib-nfe-*")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.RespostaErroAPI{
+			Erro: "Erro ao criar diretório temporário",
+		})
+		return
+	}
+	defer os.RemoveAll(tmpDir)
+
+	caminho, err := salvarUpload(c, "file", tmpDir, ".xlsx")
+	if corpoExcedido(err) {
+		c.JSON(http.StatusRequestEntityTooLarge, domain.RespostaErroAPI{
+			Erro: fmt.Sprintf("Upload acima do limite de %d MB", tamanhoMaximoCorpoNFe>>20),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.RespostaErroAPI{
+			Erro:     "Arquivo não fornecido ou inválido",
+			Detalhes: err.Error(),
+		})
+		return
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil || len(form.File["xmls"]) == 0 {
+		c.JSON(http.StatusBadRequest, domain.RespostaErroAPI{
+			Erro:     "Nenhum XML de NF-e fornecido",
+			Detalhes: "envie um ou mais arquivos .xml ou .zip no campo 'xmls'",
+		})
+		return
+	}
+
+	dirXMLs := filepath.Join(tmpDir, "xmls")
+	for i, arquivo := range form.File["xmls"] {
+		nome := filepath.Base(arquivo.Filename)
+		extensao := strings.ToLower(filepath.Ext(nome))
+		if extensao != ".xml" && extensao != ".zip" {
+			c.JSON(http.StatusBadRequest, domain.RespostaErroAPI{
+				Erro:     "Arquivo de NF-e inválido",
+				Detalhes: fmt.Sprintf("apenas arquivos .xml ou .zip são aceitos (recebido: '%s')", nome),
+			})
+			return
+		}
+		// Subpasta por arquivo: uploads com o mesmo nome não se sobrescrevem
+		if err := c.SaveUploadedFile(arquivo, filepath.Join(dirXMLs, fmt.Sprint(i), nome)); err != nil {
+			c.JSON(http.StatusInternalServerError, domain.RespostaErroAPI{
+				Erro: fmt.Sprintf("Erro ao salvar '%s': %v", nome, err),
+			})
+			return
+		}
+	}
+
+	cabecalhos, rows, err := excel.LerLinhas(caminho, h.cfg.SheetPadrao)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.RespostaErroAPI{
+			Erro:     "Erro ao ler planilha",
+			Detalhes: err.Error(),
+		})
+		return
+	}
+
+	lote, err := nfe.Carregar(dirXMLs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.RespostaErroAPI{
+			Erro:     "Erro ao ler XMLs de NF-e",
+			Detalhes: err.Error(),
+		})
+		return
+	}
+
+	inicio := time.Now()
+	conferencia := nfe.Conferir(filepath.Base(caminho), cabecalhos, rows, lote)
+	h.registrarOperacao(c, operacaoNFe, caminho, time.Since(inicio))
+
+	c.JSON(http.StatusOK, conferencia)
+}
+
+// corpoExcedido informa se o erro veio do limite de tamanho do corpo da requisição
+func corpoExcedido(err error) bool {
+	var excedido *http.MaxBytesError
+	return errors.As(err, &excedido)
+}
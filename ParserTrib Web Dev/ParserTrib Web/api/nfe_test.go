@@ -0,0 +1,66 @@
+package api
+
+import (
+	"ParserTrib/internal/domain"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"testing"
+)
+
+func TestCorpoExcedido(t *testing.T) {
+	casos := []struct {
+		nome     string
+		err      error
+		esperado bool
+	}{
+		{nome: "sem erro"},
+		{nome: "outro erro", err: errors.New("multipart: NextPart: EOF")},
+		{nome: "limite do corpo", err: &http.MaxBytesError{Limit: tamanhoMaximoCorpoNFe}, esperado: true},
+		{nome: "limite do corpo encapsulado", err: fmt.Errorf("erro ao salvar: %w", &http.MaxBytesError{Limit: 1}), esperado: true},
+	}
+
+	for _, caso := range casos {
+		if obtido := corpoExcedido(caso.err); obtido != caso.esperado {
+			t.Errorf("%s: corpoExcedido = %v, esperado %v", caso.nome, obtido, caso.esperado)
+		}
+	}
+}
+
+func TestConferirNFeUploadAcimaDoLimite(t *testing.T) {
+	router := novoRouterTeste()
+
+	// O corpo é gerado sob demanda: a planilha passa do limite sem ocupar memória no teste
+	leitor, escritor := io.Pipe()
+	formulario := multipart.NewWriter(escritor)
+	go func() {
+		parte, err := formulario.CreateFormFile("file", "produtos.xlsx")
+		if err == nil {
+			bloco := make([]byte, 1<<20)
+			for i := 0; i <= tamanhoMaximoCorpoNFe>>20 && err == nil; i++ {
+				_, err = parte.Write(bloco)
+			}
+		}
+		if err == nil {
+			err = formulario.Close()
+		}
+		escritor.CloseWithError(err)
+	}()
+
+	w := enviar(router, http.MethodPost, "/api/nfe", formulario.FormDataContentType(), leitor)
+	leitor.Close()
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("status = %d, esperado %d: %s", w.Code, http.StatusRequestEntityTooLarge, w.Body.String())
+	}
+	var resposta domain.RespostaErroAPI
+	if err := json.Unmarshal(w.Body.Bytes(), &resposta); err != nil {
+		t.Fatalf("resposta inválida: %v", err)
+	}
+	if esperado := fmt.Sprintf("Upload acima do limite de %d MB", tamanhoMaximoCorpoNFe>>20); resposta.Erro != esperado {
+		t.Errorf("erro = %q, esperado %q", resposta.Erro, esperado)
+	}
+}
@@ -0,0 +1,20 @@
+package api
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// especificacaoOpenAPI é o documento OpenAPI 3 que descreve o contrato da API.
+// Mantenha-o em sincronia com os tipos de domain — o teste em openapi_test.go falha se divergirem.
+//
+//go:embed openapi.json
+var especificacaoOpenAPI []byte
+
+// OpenAPI é o endpoint GET /api/openapi.json
+// Retorna a especificação OpenAPI 3 da API
+func (h *Handler) OpenAPI(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", especificacaoOpenAPI)
+}
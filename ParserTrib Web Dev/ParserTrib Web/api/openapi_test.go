@@ -0,0 +1,223 @@
+package api
+
+import (
+	"ParserTrib/internal/config"
+	"ParserTrib/internal/domain"
+	"ParserTrib/internal/excel"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// schemaOpenAPI é o subconjunto de um schema OpenAPI usado nas verificações
+type schemaOpenAPI struct {
+	Type       string                   `json:"type"`
+	Required   []string                 `json:"required"`
+	Properties map[string]schemaOpenAPI `json:"properties"`
+	Items      *schemaOpenAPI           `json:"items"`
+	Ref        string                   `json:"$ref"`
+	Enum       []string                 `json:"enum"`
+}
+
+type documentoOpenAPI struct {
+	OpenAPI    string                                `json:"openapi"`
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas map[string]schemaOpenAPI `json:"schemas"`
+	} `json:"components"`
+}
+
+// tiposDocumentados liga cada schema da especificação ao tipo Go que ele descreve
+var tiposDocumentados = map[string]reflect.Type{
+	"RespostaValidacaoAPI": reflect.TypeOf(domain.RespostaValidacaoAPI{}),
+	"ErroValidacao":        reflect.TypeOf(domain.ErroValidacao{}),
+	"RespostaErroAPI":      reflect.TypeOf(domain.RespostaErroAPI{}),
+
+	"RespostaValidacaoLinhasAPI": reflect.TypeOf(domain.RespostaValidacaoLinhasAPI{}),
+	"ResultadoItem":              reflect.TypeOf(domain.ResultadoItem{}),
+	"ErroCampo":                  reflect.TypeOf(domain.ErroCampo{}),
+	"PerfilColuna":               reflect.TypeOf(domain.PerfilColuna{}),
+	"GrupoDuplicado":             reflect.TypeOf(domain.GrupoDuplicado{}),
+	"SugestaoNCM":                reflect.TypeOf(domain.SugestaoNCM{}),
+	"Comparacao":                 reflect.TypeOf(domain.Comparacao{}),
+	"ConferenciaNFe":             reflect.TypeOf(domain.ConferenciaNFe{}),
+	"DivergenciaNFe":             reflect.TypeOf(domain.DivergenciaNFe{}),
+	"ItemNFe":                    reflect.TypeOf(domain.ItemNFe{}),
+	"FrequenciaValor":            reflect.TypeOf(domain.FrequenciaValor{}),
+	"CoberturaColuna":            reflect.TypeOf(domain.CoberturaColuna{}),
+}
+
+func carregarEspecificacao(t *testing.T) documentoOpenAPI {
+	t.Helper()
+	var doc documentoOpenAPI
+	if err := json.Unmarshal(especificacaoOpenAPI, &doc); err != nil {
+		t.Fatalf("openapi.json inválido: %v", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		t.Fatalf("versão OpenAPI inesperada: %q", doc.OpenAPI)
+	}
+	return doc
+}
+
+// tipoOpenAPI traduz o Kind de um campo Go para o "type" esperado no schema
+func tipoOpenAPI(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func TestSchemasCorrespondemAosTiposGo(t *testing.T) {
+	doc := carregarEspecificacao(t)
+
+	for nome, tipo := range tiposDocumentados {
+		schema, ok := doc.Components.Schemas[nome]
+		if !ok {
+			t.Errorf("schema %s ausente em openapi.json", nome)
+			continue
+		}
+
+		obrigatorios := make(map[string]bool)
+		for _, campo := range schema.Required {
+			obrigatorios[campo] = true
+		}
+
+		camposGo := make(map[string]bool)
+		for i := 0; i < tipo.NumField(); i++ {
+			campo := tipo.Field(i)
+			tag := campo.Tag.Get("json")
+			if tag == "-" || !campo.IsExported() {
+				continue
+			}
+			partes := strings.Split(tag, ",")
+			nomeJSON := partes[0]
+			if nomeJSON == "" {
+				nomeJSON = campo.Name
+			}
+			omitempty := false
+			for _, opcao := range partes[1:] {
+				omitempty = omitempty || opcao == "omitempty"
+			}
+			camposGo[nomeJSON] = true
+
+			prop, ok := schema.Properties[nomeJSON]
+			if !ok {
+				t.Errorf("%s.%s (json %q) não está documentado em openapi.json", nome, campo.Name, nomeJSON)
+				continue
+			}
+			if prop.Ref == "" && prop.Type != tipoOpenAPI(campo.Type) {
+				t.Errorf("%s.%s: tipo no spec %q, esperado %q", nome, nomeJSON, prop.Type, tipoOpenAPI(campo.Type))
+			}
+			if !omitempty && !obrigatorios[nomeJSON] {
+				t.Errorf("%s.%s sempre é serializado mas não consta em required", nome, nomeJSON)
+			}
+			if omitempty && obrigatorios[nomeJSON] {
+				t.Errorf("%s.%s é omitempty mas consta em required", nome, nomeJSON)
+			}
+		}
+
+		for prop := range schema.Properties {
+			if !camposGo[prop] {
+				t.Errorf("%s.%s documentado em openapi.json não existe na struct Go", nome, prop)
+			}
+		}
+	}
+}
+
+func TestEnumTipoCorrespondeAoResultado(t *testing.T) {
+	doc := carregarEspecificacao(t)
+
+	// Um erro em cada categoria do resultado
+	erro := []domain.ErroValidacao{{Linha: 2, Coluna: "A", NomeColuna: "X", Mensagem: "teste"}}
+	var resultado domain.ResultadoValidacaoCompleto
+	valor := reflect.ValueOf(&resultado).Elem()
+	for i := 0; i < valor.NumField(); i++ {
+		if valor.Field(i).Type() == reflect.TypeOf(erro) {
+			valor.Field(i).Set(reflect.ValueOf(erro))
+		}
+	}
+
+	var produzidos []string
+	resposta := resultado.ToRespostaAPI()
+	for _, d := range append(resposta.Detalhes, resposta.Avisos...) {
+		produzidos = append(produzidos, d.Tipo)
+	}
+	documentados := append([]string(nil), doc.Components.Schemas["TipoErro"].Enum...)
+
+	sort.Strings(produzidos)
+	sort.Strings(documentados)
+	if !reflect.DeepEqual(produzidos, documentados) {
+		t.Errorf("enum TipoErro diverge: spec %v, ToRespostaAPI %v", documentados, produzidos)
+	}
+}
+
+func TestEnumCodigoRegraCorrespondeAoCatalogo(t *testing.T) {
+	doc := carregarEspecificacao(t)
+
+	var catalogo []string
+	for _, regra := range excel.CatalogoRegras {
+		catalogo = append(catalogo, regra.Codigo)
+	}
+	documentados := append([]string(nil), doc.Components.Schemas["CodigoRegra"].Enum...)
+
+	sort.Strings(catalogo)
+	sort.Strings(documentados)
+	if !reflect.DeepEqual(catalogo, documentados) {
+		t.Errorf("enum CodigoRegra diverge: spec %v, catálogo %v", documentados, catalogo)
+	}
+
+	severidades := append([]string(nil), doc.Components.Schemas["Severidade"].Enum...)
+	if !reflect.DeepEqual(severidades, domain.Severidades) {
+		t.Errorf("enum Severidade diverge: spec %v, domain %v", severidades, domain.Severidades)
+	}
+}
+
+func TestRotasDocumentadas(t *testing.T) {
+	doc := carregarEspecificacao(t)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler := NovoHandler(config.Nova(), slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	handler.RegistrarRotas(router.Group("/api/v1"))
+	handler.RegistrarRotasRaiz(router)
+
+	registradas := make(map[string]bool)
+	for _, rota := range router.Routes() {
+		registradas[rota.Method+" "+rota.Path] = true
+	}
+
+	documentadas := make(map[string]bool)
+	for caminho, metodos := range doc.Paths {
+		for metodo := range metodos {
+			documentadas[strings.ToUpper(metodo)+" "+caminho] = true
+		}
+	}
+
+	for rota := range registradas {
+		if !documentadas[rota] {
+			t.Errorf("rota %s registrada mas não documentada em openapi.json", rota)
+		}
+	}
+	for rota := range documentadas {
+		if !registradas[rota] {
+			t.Errorf("rota %s documentada mas não registrada", rota)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"ParserTrib/internal/domain"
+	"ParserTrib/internal/excel"
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Headers da resposta de POST /api/separar com a contagem de linhas de cada planilha
+const (
+	cabecalhoLinhasValidas    = "X-Linhas-Validas"
+	cabecalhoLinhasRejeitadas = "X-Linhas-Rejeitadas"
+)
+
+// Separar é o endpoint POST /api/separar
+// Recebe um arquivo .xlsx via multipart/form-data (campo "file") e devolve um .zip com duas planilhas:
+// <nome>_validas.xlsx (linhas sem erros) e <nome>_rejeitadas.xlsx (linhas com erro + coluna de mensagens)
+func (h *Handler) Separar(c *gin.Context) {
+	tmpDir, err := os.MkdirTemp("", "parsertrib-separar-*")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.RespostaErroAPI{
+			Erro: "Erro ao criar diretório temporário",
+		})
+		return
+	}
+	defer os.RemoveAll(tmpDir)
+
+	caminho, err := salvarUpload(c, "file", tmpDir, ".xlsx")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.RespostaErroAPI{
+			Erro:     "Arquivo não fornecido ou inválido",
+			Detalhes: err.Error(),
+		})
+		return
+	}
+
+	uf, err := h.ufDaRequisicao(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.RespostaErroAPI{
+			Erro: err.Error(),
+		})
+		return
+	}
+
+	inicio := time.Now()
+	resultado, err := h.validarArquivo(caminho, h.opcoesValidacao(uf))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.RespostaErroAPI{
+			Erro:     "Erro ao validar arquivo",
+			Detalhes: err.Error(),
+		})
+		return
+	}
+
+	nome := strings.TrimSuffix(filepath.Base(caminho), filepath.Ext(caminho))
+	destinoValidas := filepath.Join(tmpDir, nome+"_validas.xlsx")
+	destinoRejeitadas := filepath.Join(tmpDir, nome+"_rejeitadas.xlsx")
+	separacao, err := excel.SepararLinhas(caminho, h.cfg.SheetPadrao, resultado, destinoValidas, destinoRejeitadas)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.RespostaErroAPI{
+			Erro: err.Error(),
+		})
+		return
+	}
+
+	h.registrarOperacao(c, operacaoSeparar, caminho, time.Since(inicio))
+
+	pacote, err := compactar(destinoValidas, destinoRejeitadas)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.RespostaErroAPI{
+			Erro: fmt.Sprintf("Erro ao compactar planilhas: %v", err),
+		})
+		return
+	}
+
+	c.Header(cabecalhoLinhasValidas, strconv.Itoa(separacao.Validas))
+	c.Header(cabecalhoLinhasRejeitadas, strconv.Itoa(separacao.Rejeitadas))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s_separado.zip"`, nome))
+	c.Data(http.StatusOK, "application/zip", pacote)
+}
+
+// compactar junta os arquivos informados em um .zip em memória
+func compactar(caminhos ...string) ([]byte, error) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+
+	for _, caminho := range caminhos {
+		origem, err := os.Open(caminho)
+		if err != nil {
+			return nil, err
+		}
+		destino, err := w.CreateHeader(&zip.FileHeader{
+			Name:     filepath.Base(caminho),
+			Method:   zip.Deflate,
+			Modified: time.Now(),
+		})
+		if err == nil {
+			_, err = io.Copy(destino, origem)
+		}
+		origem.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -4,6 +4,7 @@ import (
 	"ParserTrib/api"
 	"ParserTrib/internal/config"
 	"fmt"
+	"log/slog"
 	"os"
 
 	"github.com/gin-contrib/cors"
@@ -14,7 +15,15 @@ import (
 func IniciarServidor(cfg *config.Config) {
 	gin.SetMode(gin.ReleaseMode)
 
-	router := gin.Default()
+	// Logs estruturados em JSON no lugar do logger texto padrão do Gin
+	log := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	slog.SetDefault(log)
+
+	router := gin.New()
+	router.MaxMultipartMemory = 8 << 20 // acima disso, as partes do upload vão para arquivos temporários
+	router.Use(gin.Recovery())
+	router.Use(api.IDRequisicao())
+	router.Use(api.LogEstruturado(log))
 
 	// CORS — permite requisições do frontend em desenvolvimento
 	router.Use(cors.New(cors.Config{
@@ -24,18 +33,16 @@ func IniciarServidor(cfg *config.Config) {
 			"http://192.168.0.189:8080",
 		},
 		AllowMethods:     []string{"GET", "POST", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "X-Request-ID"},
+		ExposeHeaders:    []string{"X-Request-ID", "X-Linhas-Validas", "X-Linhas-Rejeitadas", "Content-Disposition"},
 		AllowCredentials: true,
 	}))
 
-	// Rotas
-	handler := api.NovoHandler(cfg)
-	router.POST("/api/validar", handler.ValidarExcel)
-
-	// Health check — útil pra confirmar que o servidor tá rodando
-	router.GET("/api/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{"status": "ok"})
-	})
+	// Rotas — /api/v1 é a versão documentada; /api mantém compatibilidade com clientes antigos
+	handler := api.NovoHandler(cfg, log)
+	handler.RegistrarRotas(router.Group("/api/v1"))
+	handler.RegistrarRotas(router.Group("/api"))
+	handler.RegistrarRotasRaiz(router)
 
 	// Porta: usa env PORT se existir, senão 3000
 	porta := os.Getenv("PORT")
@@ -44,8 +51,13 @@ func IniciarServidor(cfg *config.Config) {
 	}
 
 	fmt.Printf("🚀 Servidor iniciado em http://localhost:%s\n", porta)
-	fmt.Printf("📌 Endpoint: POST /api/validar\n")
-	fmt.Printf("📌 Health:   GET  /api/health\n\n")
+	fmt.Printf("📌 Endpoint: POST /api/v1/validar\n")
+	fmt.Printf("📌 Comparar: POST /api/v1/comparar\n")
+	fmt.Printf("📌 Separar:  POST /api/v1/separar\n")
+	fmt.Printf("📌 NF-e:     POST /api/v1/nfe\n")
+	fmt.Printf("📌 Health:   GET  /api/v1/health\n")
+	fmt.Printf("📌 OpenAPI:  GET  /api/openapi.json\n")
+	fmt.Printf("📌 Métricas: GET  /metrics\n\n")
 
 	if err := router.Run(":" + porta); err != nil {
 		fmt.Printf("❌ Erro ao iniciar servidor: %v\n", err)
@@ -0,0 +1,110 @@
+package baseline
+
+// Supressão de erros já analisados e aceitos: o baseline é gerado a partir de um resultado anterior
+// (JSON da API ou o resultado salvo pelo CLI) e aplicado às validações seguintes do mesmo arquivo
+
+import (
+	"ParserTrib/internal/domain"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Gerar cria o baseline com todos os problemas do resultado, inclusive os que já estavam ignorados
+func Gerar(resultado domain.RespostaValidacaoAPI, origem string) domain.Baseline {
+	b := domain.Baseline{
+		GeradoEm:   time.Now(),
+		Origem:     origem,
+		Supressoes: make([]domain.Supressao, 0),
+	}
+
+	vistas := make(map[domain.Supressao]bool)
+	problemas := append(append(append([]domain.ErroValidacao(nil), resultado.Detalhes...), resultado.Avisos...), resultado.Ignorados...)
+	for _, e := range problemas {
+		s := supressaoDe(resultado.NomeArquivo, e)
+		if vistas[s] {
+			continue
+		}
+		vistas[s] = true
+		b.Supressoes = append(b.Supressoes, s)
+	}
+	return b
+}
+
+// Salvar grava o baseline em JSON indentado
+func Salvar(b domain.Baseline, caminho string) error {
+	dados, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("erro ao serializar baseline: %w", err)
+	}
+	if err := os.WriteFile(caminho, dados, 0644); err != nil {
+		return fmt.Errorf("erro ao salvar baseline: %w", err)
+	}
+	return nil
+}
+
+// Carregar lê o baseline. Arquivo inexistente não é erro: devolve um baseline vazio.
+func Carregar(caminho string) (*domain.Baseline, error) {
+	dados, err := os.ReadFile(caminho)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &domain.Baseline{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler baseline: %w", err)
+	}
+
+	var b domain.Baseline
+	if err := json.Unmarshal(dados, &b); err != nil {
+		return nil, fmt.Errorf("erro ao interpretar baseline '%s': %w", caminho, err)
+	}
+	return &b, nil
+}
+
+// CarregarResultado lê um resultado de validação salvo (resposta da API ou resultado do CLI)
+func CarregarResultado(caminho string) (domain.RespostaValidacaoAPI, error) {
+	var resultado domain.RespostaValidacaoAPI
+
+	dados, err := os.ReadFile(caminho)
+	if err != nil {
+		return resultado, fmt.Errorf("erro ao ler resultado: %w", err)
+	}
+	if err := json.Unmarshal(dados, &resultado); err != nil {
+		return resultado, fmt.Errorf("erro ao interpretar resultado '%s': %w", caminho, err)
+	}
+	return resultado, nil
+}
+
+// Aplicar move para os ignorados os problemas do resultado que constam no baseline
+// para o mesmo arquivo. Retorna quantos foram suprimidos.
+func Aplicar(b *domain.Baseline, resultado *domain.ResultadoValidacaoCompleto) int {
+	if b == nil || len(b.Supressoes) == 0 {
+		return 0
+	}
+
+	aceitas := make(map[domain.Supressao]bool, len(b.Supressoes))
+	for _, s := range b.Supressoes {
+		aceitas[s] = true
+	}
+
+	antes := len(resultado.Ignorados)
+	resultado.Suprimir(func(e domain.ErroValidacao) bool {
+		return aceitas[supressaoDe(resultado.NomeArquivo, e)]
+	})
+	return len(resultado.Ignorados) - antes
+}
+
+// supressaoDe monta a identidade do problema. O arquivo é comparado pelo nome (sem pasta e sem
+// diferenciar maiúsculas), para que novas versões enviadas com o mesmo nome continuem casando.
+func supressaoDe(nomeArquivo string, e domain.ErroValidacao) domain.Supressao {
+	return domain.Supressao{
+		Arquivo: strings.ToLower(filepath.Base(nomeArquivo)),
+		Regra:   e.Codigo,
+		Chave:   e.ChaveLinha(),
+		Coluna:  e.NomeColuna,
+	}
+}
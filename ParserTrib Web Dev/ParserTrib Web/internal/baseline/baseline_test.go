@@ -0,0 +1,100 @@
+package baseline
+
+import (
+	"ParserTrib/internal/domain"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func erroNCM(linha int, chave string) domain.ErroValidacao {
+	return domain.ErroValidacao{Linha: linha, Chave: chave, NomeColuna: "NCM", Codigo: "NCM001"}
+}
+
+func TestGerar(t *testing.T) {
+	resultado := domain.RespostaValidacaoAPI{
+		NomeArquivo: "uploads/Produtos.xlsx",
+		Detalhes:    []domain.ErroValidacao{erroNCM(2, "P1"), erroNCM(9, "P1")},
+		Avisos:      []domain.ErroValidacao{{Linha: 3, NomeColuna: "Descrição", Codigo: "DUP001"}},
+		Ignorados:   []domain.ErroValidacao{erroNCM(4, "P2")},
+	}
+
+	esperadas := []domain.Supressao{
+		{Arquivo: "produtos.xlsx", Regra: "NCM001", Chave: "P1", Coluna: "NCM"},
+		{Arquivo: "produtos.xlsx", Regra: "DUP001", Chave: "linha 3", Coluna: "Descrição"},
+		{Arquivo: "produtos.xlsx", Regra: "NCM001", Chave: "P2", Coluna: "NCM"},
+	}
+	b := Gerar(resultado, "resultado.json")
+	if !slices.Equal(b.Supressoes, esperadas) {
+		t.Errorf("supressões = %+v, esperado %+v", b.Supressoes, esperadas)
+	}
+	if b.Origem != "resultado.json" || b.GeradoEm.IsZero() {
+		t.Errorf("origem = %q, gerado em %v", b.Origem, b.GeradoEm)
+	}
+}
+
+func TestAplicar(t *testing.T) {
+	b := &domain.Baseline{Supressoes: []domain.Supressao{
+		{Arquivo: "produtos.xlsx", Regra: "NCM001", Chave: "P1", Coluna: "NCM"},
+	}}
+
+	casos := []struct {
+		nome       string
+		baseline   *domain.Baseline
+		arquivo    string
+		suprimidos int
+		restantes  int
+	}{
+		{nome: "mesmo arquivo com outra caixa e em outra pasta", baseline: b, arquivo: "/tmp/envio/PRODUTOS.xlsx", suprimidos: 1, restantes: 1},
+		{nome: "outro arquivo", baseline: b, arquivo: "estoque.xlsx", restantes: 2},
+		{nome: "sem baseline", arquivo: "produtos.xlsx", restantes: 2},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			resultado := domain.ResultadoValidacaoCompleto{
+				NomeArquivo: caso.arquivo,
+				ErrosNCM:    []domain.ErroValidacao{erroNCM(2, "P1"), erroNCM(3, "P2")},
+			}
+
+			if suprimidos := Aplicar(caso.baseline, &resultado); suprimidos != caso.suprimidos {
+				t.Errorf("suprimidos = %d, esperado %d", suprimidos, caso.suprimidos)
+			}
+			if len(resultado.ErrosNCM) != caso.restantes {
+				t.Errorf("erros restantes = %d, esperado %d", len(resultado.ErrosNCM), caso.restantes)
+			}
+			if len(resultado.Ignorados) != caso.suprimidos {
+				t.Errorf("ignorados = %d, esperado %d", len(resultado.Ignorados), caso.suprimidos)
+			}
+		})
+	}
+}
+
+func TestSalvarCarregar(t *testing.T) {
+	caminho := filepath.Join(t.TempDir(), "baseline.json")
+
+	vazio, err := Carregar(caminho)
+	if err != nil || len(vazio.Supressoes) != 0 {
+		t.Fatalf("baseline inexistente = %+v, %v; esperado vazio e sem erro", vazio, err)
+	}
+
+	b := domain.Baseline{Origem: "resultado.json", Supressoes: []domain.Supressao{{Arquivo: "produtos.xlsx", Regra: "NCM001", Chave: "P1", Coluna: "NCM"}}}
+	if err := Salvar(b, caminho); err != nil {
+		t.Fatalf("Salvar: %v", err)
+	}
+	lido, err := Carregar(caminho)
+	if err != nil {
+		t.Fatalf("Carregar: %v", err)
+	}
+	if !slices.Equal(lido.Supressoes, b.Supressoes) {
+		t.Errorf("supressões lidas = %+v, esperado %+v", lido.Supressoes, b.Supressoes)
+	}
+
+	if err := os.WriteFile(caminho, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Carregar(caminho); err == nil {
+		t.Error("baseline inválido: esperado erro")
+	}
+}
@@ -0,0 +1,133 @@
+package cbenef
+
+// Tabela local de códigos de benefício fiscal (cBenef) por UF, com os CSTs do ICMS em que cada
+// código pode ser usado
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// regexCodigo segue o formato do cBenef: sigla da UF + 6 caracteres (ex.: PR800001, RJ810001)
+var regexCodigo = regexp.MustCompile(`^[A-Z]{2}[0-9A-Z]{6}$`)
+
+// Beneficio é uma linha da tabela
+type Beneficio struct {
+	UF        string
+	Codigo    string
+	CSTs      []string // CSTs do ICMS (2 dígitos) em que o código é aceito
+	Descricao string
+}
+
+// Permite informa se o benefício pode ser usado com o CST
+func (b Beneficio) Permite(cst string) bool {
+	for _, permitido := range b.CSTs {
+		if permitido == cst {
+			return true
+		}
+	}
+	return false
+}
+
+// Tabela indexa os benefícios por UF e código
+type Tabela struct {
+	porUF map[string]map[string]Beneficio
+}
+
+// CarregarCSV lê a tabela de um CSV com as colunas UF, cBenef, CSTs e descrição (separador ";" ou ",").
+// Os CSTs da terceira coluna são separados por espaço, vírgula ou "/" (ex.: "40 41"). Linhas de
+// cabeçalho e códigos fora do formato são ignorados.
+func CarregarCSV(caminho string) (*Tabela, error) {
+	f, err := os.Open(caminho)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir tabela cBenef: %w", err)
+	}
+	defer f.Close()
+
+	leitor := bufio.NewReader(f)
+	primeiraLinha, _ := leitor.Peek(4096)
+
+	r := csv.NewReader(leitor)
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	if strings.Count(string(primeiraLinha), ";") > 0 {
+		r.Comma = ';'
+	}
+
+	tabela := &Tabela{porUF: make(map[string]map[string]Beneficio)}
+	for {
+		registro, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler tabela cBenef: %w", err)
+		}
+		if len(registro) < 3 {
+			continue
+		}
+
+		beneficio := Beneficio{
+			UF:     strings.ToUpper(strings.TrimSpace(strings.TrimPrefix(registro[0], "\ufeff"))),
+			Codigo: strings.ToUpper(strings.TrimSpace(registro[1])),
+		}
+		if !regexCodigo.MatchString(beneficio.Codigo) {
+			continue
+		}
+		beneficio.CSTs = strings.FieldsFunc(registro[2], func(r rune) bool {
+			return r == ' ' || r == ',' || r == '/'
+		})
+		if len(registro) > 3 {
+			beneficio.Descricao = strings.TrimSpace(registro[3])
+		}
+
+		if tabela.porUF[beneficio.UF] == nil {
+			tabela.porUF[beneficio.UF] = make(map[string]Beneficio)
+		}
+		tabela.porUF[beneficio.UF][beneficio.Codigo] = beneficio
+	}
+
+	if len(tabela.porUF) == 0 {
+		return nil, fmt.Errorf("tabela cBenef '%s' não tem nenhum código válido", caminho)
+	}
+	return tabela, nil
+}
+
+// Total devolve a quantidade de códigos carregados
+func (t *Tabela) Total() int {
+	total := 0
+	for _, codigos := range t.porUF {
+		total += len(codigos)
+	}
+	return total
+}
+
+// UFs devolve as UFs presentes na tabela, em ordem alfabética
+func (t *Tabela) UFs() []string {
+	ufs := make([]string, 0, len(t.porUF))
+	for uf := range t.porUF {
+		ufs = append(ufs, uf)
+	}
+	sort.Strings(ufs)
+	return ufs
+}
+
+// Possui informa se a tabela tem códigos para a UF (só essas UFs são verificadas)
+func (t *Tabela) Possui(uf string) bool {
+	return t != nil && len(t.porUF[uf]) > 0
+}
+
+// Buscar devolve o benefício com o código informado na UF
+func (t *Tabela) Buscar(uf, codigo string) (Beneficio, bool) {
+	if t == nil {
+		return Beneficio{}, false
+	}
+	beneficio, ok := t.porUF[uf][strings.ToUpper(codigo)]
+	return beneficio, ok
+}
@@ -0,0 +1,103 @@
+package comparacao
+
+// Comparação entre duas validações da mesma planilha (ex.: a versão enviada e a corrigida pelo cliente)
+
+import (
+	"ParserTrib/internal/baseline"
+	"ParserTrib/internal/domain"
+	"ParserTrib/internal/excel"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// identidade é o que torna um problema "o mesmo" nas duas versões: regra, chave da linha e coluna.
+// O número da linha fica de fora para que linhas inseridas ou removidas não quebrem a comparação.
+type identidade struct {
+	regra  string
+	chave  string
+	coluna string
+}
+
+func identidadeDe(e domain.ErroValidacao) identidade {
+	return identidade{regra: e.Codigo, chave: e.ChaveLinha(), coluna: e.NomeColuna}
+}
+
+// Carregar obtém o resultado de um dos lados da comparação: um resultado salvo (.json, gerado pelo CLI
+// ou devolvido pela API) ou uma planilha (.xlsx), que é validada com as opções e o baseline informados.
+// Em resultados salvos, a chave de cada linha é a da coluna-chave usada quando foram gerados.
+func Carregar(caminho, sheetName string, opcoes excel.OpcoesValidacao, supressoes *domain.Baseline) (domain.RespostaValidacaoAPI, error) {
+	switch strings.ToLower(filepath.Ext(caminho)) {
+	case ".json":
+		return baseline.CarregarResultado(caminho)
+	case ".xlsx":
+		resultado, err := excel.ValidarArquivo(caminho, sheetName, opcoes)
+		if err != nil {
+			return domain.RespostaValidacaoAPI{}, err
+		}
+		baseline.Aplicar(supressoes, &resultado)
+		return resultado.ToRespostaAPI(), nil
+	default:
+		return domain.RespostaValidacaoAPI{}, fmt.Errorf("'%s': informe uma planilha .xlsx ou um resultado .json", filepath.Base(caminho))
+	}
+}
+
+// Comparar classifica os problemas (erros e avisos, sem os ignorados pelo baseline) em corrigidos,
+// novos e persistentes. Problemas repetidos com a mesma identidade são casados um a um.
+func Comparar(anterior, atual domain.RespostaValidacaoAPI) domain.Comparacao {
+	c := domain.Comparacao{
+		ArquivoAnterior: anterior.NomeArquivo,
+		ArquivoAtual:    atual.NomeArquivo,
+		Corrigidos:      make([]domain.ErroValidacao, 0),
+		Novos:           make([]domain.ErroValidacao, 0),
+		Persistentes:    make([]domain.ErroValidacao, 0),
+	}
+
+	pendentes := make(map[identidade][]domain.ErroValidacao)
+	var ordem []identidade
+	for _, e := range problemas(anterior) {
+		id := identidadeDe(e)
+		if _, existe := pendentes[id]; !existe {
+			ordem = append(ordem, id)
+		}
+		pendentes[id] = append(pendentes[id], e)
+	}
+
+	for _, e := range problemas(atual) {
+		id := identidadeDe(e)
+		if len(pendentes[id]) > 0 {
+			pendentes[id] = pendentes[id][1:]
+			c.Persistentes = append(c.Persistentes, e)
+			continue
+		}
+		c.Novos = append(c.Novos, e)
+	}
+
+	for _, id := range ordem {
+		c.Corrigidos = append(c.Corrigidos, pendentes[id]...)
+	}
+
+	for _, lista := range [][]domain.ErroValidacao{c.Corrigidos, c.Novos, c.Persistentes} {
+		ordenarPorLinha(lista)
+	}
+	c.TotalCorrigidos = len(c.Corrigidos)
+	c.TotalNovos = len(c.Novos)
+	c.TotalPersistentes = len(c.Persistentes)
+
+	return c
+}
+
+func problemas(r domain.RespostaValidacaoAPI) []domain.ErroValidacao {
+	return append(append([]domain.ErroValidacao(nil), r.Detalhes...), r.Avisos...)
+}
+
+// ordenarPorLinha ordena por linha e depois por coluna
+func ordenarPorLinha(erros []domain.ErroValidacao) {
+	sort.SliceStable(erros, func(i, j int) bool {
+		if erros[i].Linha != erros[j].Linha {
+			return erros[i].Linha < erros[j].Linha
+		}
+		return erros[i].Coluna < erros[j].Coluna
+	})
+}
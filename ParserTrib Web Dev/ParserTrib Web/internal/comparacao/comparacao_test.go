@@ -0,0 +1,102 @@
+package comparacao
+
+import (
+	"ParserTrib/internal/domain"
+	"ParserTrib/internal/excel"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func erro(linha int, chave, coluna, codigo string) domain.ErroValidacao {
+	return domain.ErroValidacao{Linha: linha, Chave: chave, NomeColuna: coluna, Codigo: codigo}
+}
+
+func resumir(erros []domain.ErroValidacao) []string {
+	resumo := []string{}
+	for _, e := range erros {
+		resumo = append(resumo, fmt.Sprintf("%d %s %s", e.Linha, e.ChaveLinha(), e.Codigo))
+	}
+	return resumo
+}
+
+func TestComparar(t *testing.T) {
+	casos := []struct {
+		nome         string
+		anterior     []domain.ErroValidacao
+		atual        []domain.ErroValidacao
+		corrigidos   []string
+		novos        []string
+		persistentes []string
+	}{
+		{
+			nome:         "linha deslocada continua sendo o mesmo problema",
+			anterior:     []domain.ErroValidacao{erro(2, "P1", "NCM", "NCM001")},
+			atual:        []domain.ErroValidacao{erro(5, "P1", "NCM", "NCM001")},
+			corrigidos:   []string{},
+			novos:        []string{},
+			persistentes: []string{"5 P1 NCM001"},
+		},
+		{
+			nome:         "corrigidos e novos",
+			anterior:     []domain.ErroValidacao{erro(3, "P2", "CEST", "CEST001"), erro(2, "P1", "NCM", "NCM001")},
+			atual:        []domain.ErroValidacao{erro(2, "P1", "CSOSN", "CSOSN001")},
+			corrigidos:   []string{"2 P1 NCM001", "3 P2 CEST001"},
+			novos:        []string{"2 P1 CSOSN001"},
+			persistentes: []string{},
+		},
+		{
+			nome:         "repetidos são casados um a um",
+			anterior:     []domain.ErroValidacao{erro(2, "", "NCM", "NCM001"), erro(2, "", "NCM", "NCM001")},
+			atual:        []domain.ErroValidacao{erro(2, "", "NCM", "NCM001")},
+			corrigidos:   []string{"2 linha 2 NCM001"},
+			novos:        []string{},
+			persistentes: []string{"2 linha 2 NCM001"},
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			c := Comparar(
+				domain.RespostaValidacaoAPI{NomeArquivo: "v1.xlsx", Detalhes: caso.anterior},
+				domain.RespostaValidacaoAPI{NomeArquivo: "v2.xlsx", Detalhes: caso.atual},
+			)
+
+			for _, lista := range []struct {
+				nome      string
+				obtidos   []domain.ErroValidacao
+				total     int
+				esperados []string
+			}{
+				{"corrigidos", c.Corrigidos, c.TotalCorrigidos, caso.corrigidos},
+				{"novos", c.Novos, c.TotalNovos, caso.novos},
+				{"persistentes", c.Persistentes, c.TotalPersistentes, caso.persistentes},
+			} {
+				if resumo := resumir(lista.obtidos); !slices.Equal(resumo, lista.esperados) {
+					t.Errorf("%s = %q, esperado %q", lista.nome, resumo, lista.esperados)
+				}
+				if lista.total != len(lista.esperados) {
+					t.Errorf("total de %s = %d, esperado %d", lista.nome, lista.total, len(lista.esperados))
+				}
+			}
+		})
+	}
+}
+
+func TestCompararIgnoraSuprimidos(t *testing.T) {
+	anterior := domain.RespostaValidacaoAPI{Ignorados: []domain.ErroValidacao{erro(2, "P1", "NCM", "NCM001")}}
+	atual := domain.RespostaValidacaoAPI{Avisos: []domain.ErroValidacao{erro(2, "P1", "Descrição", "DUP001")}}
+
+	c := Comparar(anterior, atual)
+	if c.TotalCorrigidos != 0 || c.TotalNovos != 1 || c.TotalPersistentes != 0 {
+		t.Errorf("corrigidos/novos/persistentes = %d/%d/%d, esperado 0/1/0", c.TotalCorrigidos, c.TotalNovos, c.TotalPersistentes)
+	}
+}
+
+func TestCarregarExtensaoInvalida(t *testing.T) {
+	_, err := Carregar("produtos.csv", "Produto", excel.OpcoesValidacao{}, nil)
+	if err == nil || !strings.Contains(err.Error(), "informe uma planilha .xlsx ou um resultado .json") {
+		t.Errorf("erro = %v, esperado recusa da extensão", err)
+	}
+}
@@ -1,10 +1,30 @@
 package config
 
+import (
+	"os"
+	"strings"
+)
+
 // Config é uma struct que contem as configurações padrão do sistema
 type Config struct {
 	CaminhoPadrao string
 	SheetPadrao   string
 	DiretorioLogs string
+	TabelaNCM     string // CSV com código e descrição dos NCMs, usado nas sugestões
+	PerfilRegras  string // JSON com a severidade de cada regra (opcional)
+	Baseline      string // JSON com os erros aceitos, ignorados nas validações (opcional)
+	ColunaChave   string // coluna que identifica o produto nos erros, no baseline e nas comparações
+	ArquivoEFD    string // SPED EFD para conciliar com os registros 0200 (vazio = sem conciliação)
+	TabelaCBenef  string // CSV com os códigos de benefício (cBenef) por UF e os CSTs permitidos
+	UF            string // UF do emitente, usada na regra do cBenef (vazio = regra desativada)
+	TabelaUnidade string // CSV com as unidades de medida aceitas (opcional)
+	TabelaServico string // CSV com a lista de serviços da LC 116 (opcional)
+	TabelaReforma string // JSON versionado com CST IBS/CBS e cClassTrib (usado se o perfil habilitar as regras)
+	TabelaANP     string // CSV com os códigos de produto da ANP (opcional)
+
+	// PrefixosCombustivel são os prefixos de NCM que exigem o código ANP (vazio = padrão do validador);
+	// podem ser trocados pela variável PARSERTRIB_NCM_COMBUSTIVEL, separados por vírgula
+	PrefixosCombustivel []string
 }
 
 // Nova cria uma instância de Config com valores padrão
@@ -13,5 +33,28 @@ func Nova() *Config {
 		CaminhoPadrao: "./xlsxModels",
 		SheetPadrao:   "Produto",
 		DiretorioLogs: "./logs",
+		TabelaNCM:     "./tabelas/ncm.csv",
+		PerfilRegras:  "./perfil_regras.json",
+		Baseline:      "./baseline.json",
+		ColunaChave:   "Código",
+		TabelaCBenef:  "./tabelas/cbenef.csv",
+		UF:            strings.ToUpper(os.Getenv("PARSERTRIB_UF")),
+		TabelaUnidade: "./tabelas/unidades.csv",
+		TabelaServico: "./tabelas/lc116.csv",
+		TabelaReforma: "./tabelas/ibs_cbs.json",
+		TabelaANP:     "./tabelas/anp.csv",
+
+		PrefixosCombustivel: listaAmbiente("PARSERTRIB_NCM_COMBUSTIVEL"),
+	}
+}
+
+// listaAmbiente lê uma variável de ambiente com valores separados por vírgula (nil se vazia)
+func listaAmbiente(nome string) []string {
+	var valores []string
+	for _, valor := range strings.Split(os.Getenv(nome), ",") {
+		if valor = strings.TrimSpace(valor); valor != "" {
+			valores = append(valores, valor)
+		}
 	}
+	return valores
 }
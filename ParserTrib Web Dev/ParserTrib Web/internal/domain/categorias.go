@@ -0,0 +1,90 @@
+package domain
+
+// Tipos de erro — identificam a categoria de cada ErroValidacao na API
+const (
+	TipoVazia          = "VAZIA"
+	TipoNCM            = "NCM"
+	TipoCSTOrigem      = "CST_ORIGEM"
+	TipoCSOSN          = "CSOSN"
+	TipoTipoItem       = "TIPO_ITEM"
+	TipoFCI            = "FCI"
+	TipoCBenef         = "CBENEF"
+	TipoUnidade        = "UNIDADE"
+	TipoServico        = "SERVICO"
+	TipoANP            = "ANP"
+	TipoReforma        = "IBS_CBS"
+	TipoTexto          = "TEXTO"
+	TipoCelulaNumerica = "CELULA_NUMERICA"
+	TipoEFD            = "EFD"          // conciliação com os registros 0200 de um SPED EFD
+	TipoConsistencia   = "CONSISTENCIA" // aviso: não entra nas categorias de erro nem no total
+)
+
+// CategoriaErros associa uma lista de erros ao seu tipo e aos textos usados nos relatórios
+type CategoriaErros struct {
+	Tipo   string          // identificador usado na API (ex.: "NCM")
+	Titulo string          // título da seção no terminal e no log
+	Rotulo string          // rótulo usado nos resumos (ex.: "erros NCM")
+	Erros  []ErroValidacao // erros da categoria
+}
+
+// refCategoria aponta para a lista de erros de uma categoria dentro do resultado
+type refCategoria struct {
+	tipo   string
+	titulo string
+	rotulo string
+	erros  *[]ErroValidacao
+}
+
+// categorias é a fonte única da lista de categorias; as referências permitem alterar as listas
+func (r *ResultadoValidacaoCompleto) categorias() []refCategoria {
+	return []refCategoria{
+		{TipoVazia, "CÉLULAS VAZIAS", "células vazias", &r.ErrosVazias},
+		{TipoNCM, "ERROS DE VALIDAÇÃO NCM", "erros NCM", &r.ErrosNCM},
+		{TipoCSTOrigem, "ERROS DE VALIDAÇÃO CST ORIGEM", "erros CST Origem", &r.ErrosCSTOrigem},
+		{TipoCSOSN, "ERROS DE VALIDAÇÃO CSOSN", "erros CSOSN", &r.ErrosCSOSN},
+		{TipoTipoItem, "ERROS DE VALIDAÇÃO TIPO ITEM", "erros Tipo Item", &r.ErrosTipoItem},
+		{TipoFCI, "ERROS DE VALIDAÇÃO FCI (CONTEÚDO DE IMPORTAÇÃO)", "erros FCI", &r.ErrosFCI},
+		{TipoCBenef, "ERROS DE VALIDAÇÃO cBenef (BENEFÍCIO FISCAL DA UF)", "erros cBenef", &r.ErrosCBenef},
+		{TipoUnidade, "ERROS DE UNIDADE DE MEDIDA E FATOR DE CONVERSÃO", "erros de unidade", &r.ErrosUnidade},
+		{TipoServico, "ERROS DE ITENS DE SERVIÇO (LISTA DA LC 116)", "erros de serviço", &r.ErrosServico},
+		{TipoANP, "ERROS DE COMBUSTÍVEIS (CÓDIGO ANP)", "erros ANP", &r.ErrosANP},
+		{TipoReforma, "ERROS DE CLASSIFICAÇÃO IBS/CBS (REFORMA TRIBUTÁRIA)", "erros IBS/CBS", &r.ErrosReforma},
+		{TipoTexto, "ERROS DE TEXTO (TAMANHO, CARACTERES E ESPAÇOS)", "erros de texto", &r.ErrosTexto},
+		{TipoCelulaNumerica, "CÓDIGOS ARMAZENADOS COMO NÚMERO", "códigos armazenados como número", &r.ErrosCelulaNumerica},
+		{TipoEFD, "CONCILIAÇÃO COM O SPED EFD (REGISTRO 0200)", "divergências com o EFD", &r.ErrosEFD},
+	}
+}
+
+// Categorias retorna as categorias de erro do resultado, na ordem de exibição
+func (r ResultadoValidacaoCompleto) Categorias() []CategoriaErros {
+	refs := r.categorias()
+	categorias := make([]CategoriaErros, 0, len(refs))
+	for _, ref := range refs {
+		categorias = append(categorias, CategoriaErros{
+			Tipo:   ref.tipo,
+			Titulo: ref.titulo,
+			Rotulo: ref.rotulo,
+			Erros:  *ref.erros,
+		})
+	}
+	return categorias
+}
+
+// Suprimir move para Ignorados os erros e avisos marcados pela função (ex.: baseline de erros aceitos).
+// Os ignorados saem das categorias e, portanto, dos totais; o Tipo de cada um é preenchido.
+func (r *ResultadoValidacaoCompleto) Suprimir(suprimido func(ErroValidacao) bool) {
+	refs := append(r.categorias(), refCategoria{tipo: TipoConsistencia, erros: &r.AvisosConsistencia})
+
+	for _, ref := range refs {
+		mantidos := (*ref.erros)[:0:0]
+		for _, e := range *ref.erros {
+			if suprimido(e) {
+				e.Tipo = ref.tipo
+				r.Ignorados = append(r.Ignorados, e)
+				continue
+			}
+			mantidos = append(mantidos, e)
+		}
+		*ref.erros = mantidos
+	}
+}
@@ -3,7 +3,9 @@ package domain
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"sort"
+	"strings"
 	"time"
 )
 
@@ -37,46 +39,394 @@ type Planilha struct {
 	TotalLinhas int
 }
 
-// ErroValidacao representa um erro especifico
+// TipoCelula classifica o conteúdo armazenado numa célula do Excel
+type TipoCelula string
+
+const (
+	CelulaVazia    TipoCelula = ""
+	CelulaTexto    TipoCelula = "texto"
+	CelulaNumero   TipoCelula = "numero"
+	CelulaBooleano TipoCelula = "booleano"
+	CelulaData     TipoCelula = "data"
+	CelulaFormula  TipoCelula = "formula"
+	CelulaErro     TipoCelula = "erro"
+)
+
+// MetadadoCelula guarda como a célula está armazenada no Excel, além do texto exibido
+type MetadadoCelula struct {
+	Tipo          TipoCelula
+	FormatoNumero string // ex.: "General", "0", "0.00E+00", "@"
+	ValorBruto    string // valor gravado no arquivo, sem aplicar o formato
+}
+
+// SugestaoNCM é um NCM candidato para a descrição do produto, com a similaridade (0 a 1)
+type SugestaoNCM struct {
+	NCM       string  `json:"ncm"`
+	Descricao string  `json:"descricao"`
+	Pontuacao float64 `json:"pontuacao"`
+}
+
+// Severidades de um problema encontrado — apenas "erro" reprova a planilha
+const (
+	SeveridadeErro  = "erro"
+	SeveridadeAviso = "aviso"
+	SeveridadeInfo  = "info"
+)
+
+// Severidades lista as severidades na ordem de exibição
+var Severidades = []string{SeveridadeErro, SeveridadeAviso, SeveridadeInfo}
+
+// ErroValidacao representa um problema encontrado por uma regra, identificado pelo código estável da regra
 type ErroValidacao struct {
-	Linha      int    `json:"linha"`
-	Coluna     string `json:"coluna"`
-	NomeColuna string `json:"nomeColuna"`
-	Tipo       string `json:"tipo"`
-	Mensagem   string `json:"mensagem"`
+	Linha         int           `json:"linha"`
+	Coluna        string        `json:"coluna"`
+	NomeColuna    string        `json:"nomeColuna"`
+	Tipo          string        `json:"tipo"`
+	Codigo        string        `json:"codigo"`
+	Severidade    string        `json:"severidade"`
+	Chave         string        `json:"chave,omitempty"` // valor da coluna-chave da linha (código do produto)
+	Mensagem      string        `json:"mensagem"`
+	Sugestoes     []SugestaoNCM `json:"sugestoes,omitempty"`
+	ValorSugerido string        `json:"valorSugerido,omitempty"`
 }
 
 // String formatada de mensagem de erro para exibição
 func (e ErroValidacao) String() string {
-	return fmt.Sprintf("[ERRO] Linha %d, Coluna %s (%s): %s",
+	rotulo := "ERRO"
+	if e.Severidade != "" {
+		rotulo = strings.ToUpper(e.Severidade)
+	}
+	if e.Codigo != "" {
+		rotulo += " " + e.Codigo
+	}
+
+	texto := fmt.Sprintf("[%s] Linha %d, Coluna %s (%s): %s",
+		rotulo,
 		e.Linha,
 		e.Coluna,
 		e.NomeColuna,
 		e.Mensagem)
+
+	if len(e.Sugestoes) > 0 {
+		sugestoes := make([]string, 0, len(e.Sugestoes))
+		for _, s := range e.Sugestoes {
+			sugestoes = append(sugestoes, fmt.Sprintf("%s (%.2f)", s.NCM, s.Pontuacao))
+		}
+		texto += " | sugestões: " + strings.Join(sugestoes, ", ")
+	}
+	return texto
+}
+
+// ChaveLinha identifica a linha do problema pelo valor da coluna-chave ou, sem ele, pelo número da linha
+func (e ErroValidacao) ChaveLinha() string {
+	if e.Chave != "" {
+		return e.Chave
+	}
+	return fmt.Sprintf("linha %d", e.Linha)
+}
+
+// FrequenciaValor é um valor e quantas vezes ele aparece na coluna
+type FrequenciaValor struct {
+	Valor       string `json:"valor"`
+	Ocorrencias int    `json:"ocorrencias"`
+}
+
+// Tipos detectados pelo perfil de colunas
+const (
+	PerfilVazia    = "vazia"
+	PerfilNumerico = "numerico"
+	PerfilCodigo   = "codigo"
+	PerfilTexto    = "texto"
+	PerfilData     = "data"
+)
+
+// PerfilColuna resume o conteúdo de uma coluna da planilha (visão geral antes da importação)
+type PerfilColuna struct {
+	Coluna            string            `json:"coluna"`
+	NomeColuna        string            `json:"nomeColuna"`
+	Preenchidas       int               `json:"preenchidas"`
+	TaxaPreenchimento float64           `json:"taxaPreenchimento"` // percentual de 0 a 100
+	ValoresDistintos  int               `json:"valoresDistintos"`
+	MaisFrequentes    []FrequenciaValor `json:"maisFrequentes"`
+	TamanhoMinimo     int               `json:"tamanhoMinimo"`
+	TamanhoMaximo     int               `json:"tamanhoMaximo"`
+	TipoDetectado     string            `json:"tipoDetectado"`
+}
+
+// String formatada do perfil da coluna para exibição
+func (p PerfilColuna) String() string {
+	valores := make([]string, 0, len(p.MaisFrequentes))
+	for _, f := range p.MaisFrequentes {
+		valores = append(valores, fmt.Sprintf("'%s' (%d)", f.Valor, f.Ocorrencias))
+	}
+	if len(valores) == 0 {
+		valores = append(valores, "-")
+	}
+
+	return fmt.Sprintf("[PERFIL] Coluna %s (%s): tipo %s | preenchimento %.1f%% (%d) | %d distintos | tamanho %d-%d | mais frequentes: %s",
+		p.Coluna,
+		p.NomeColuna,
+		p.TipoDetectado,
+		p.TaxaPreenchimento,
+		p.Preenchidas,
+		p.ValoresDistintos,
+		p.TamanhoMinimo,
+		p.TamanhoMaximo,
+		strings.Join(valores, ", "))
+}
+
+// Obrigatoriedade de uma coluna no perfil de regras
+const (
+	ColunaObrigatoria = "obrigatoria"
+	ColunaOpcional    = "opcional"
+	ColunaCondicional = "condicional" // obrigatória só quando outra coluna atende à condição
+)
+
+// CoberturaColuna resume o preenchimento de uma coluna exigida: quantas células precisavam estar
+// preenchidas e quantas estão (percentual de 0 a 100)
+type CoberturaColuna struct {
+	Coluna          string  `json:"coluna"`
+	NomeColuna      string  `json:"nomeColuna"`
+	Obrigatoriedade string  `json:"obrigatoriedade"`
+	Exigidas        int     `json:"exigidas"`
+	Preenchidas     int     `json:"preenchidas"`
+	Percentual      float64 `json:"percentual"`
+}
+
+// String formatada da cobertura da coluna para exibição
+func (c CoberturaColuna) String() string {
+	return fmt.Sprintf("[COBERTURA] Coluna %s (%s): %.1f%% (%d de %d) | %s",
+		c.Coluna,
+		c.NomeColuna,
+		c.Percentual,
+		c.Preenchidas,
+		c.Exigidas,
+		c.Obrigatoriedade)
+}
+
+// Correcao registra uma célula alterada pelo modo de correção automática
+type Correcao struct {
+	Linha      int    `json:"linha"`
+	Coluna     string `json:"coluna"`
+	NomeColuna string `json:"nomeColuna"`
+	Regras     string `json:"regras"`
+	Antes      string `json:"antes"`
+	Depois     string `json:"depois"`
+}
+
+// String formatada da correção para exibição
+func (c Correcao) String() string {
+	return fmt.Sprintf("[CORRIGIDO] Linha %d, Coluna %s (%s): '%s' -> '%s' [%s]",
+		c.Linha,
+		c.Coluna,
+		c.NomeColuna,
+		c.Antes,
+		c.Depois,
+		c.Regras)
+}
+
+// Critérios usados para agrupar produtos duplicados
+const (
+	DuplicadoPorCodigo    = "CODIGO"
+	DuplicadoPorDescricao = "DESCRICAO"
+)
+
+// GrupoDuplicado reúne as linhas que representam o mesmo produto (mesmo código ou mesma descrição
+// normalizada). Divergente indica que as linhas discordam em algum campo fiscal.
+type GrupoDuplicado struct {
+	Criterio          string   `json:"criterio"`
+	Chave             string   `json:"chave"`
+	Linhas            []int    `json:"linhas"`
+	Divergente        bool     `json:"divergente"`
+	CamposDivergentes []string `json:"camposDivergentes"`
+}
+
+// String formatada do grupo de duplicados para exibição
+func (g GrupoDuplicado) String() string {
+	linhas := make([]string, 0, len(g.Linhas))
+	for _, l := range g.Linhas {
+		linhas = append(linhas, fmt.Sprintf("%d", l))
+	}
+
+	criterio := "Código"
+	if g.Criterio == DuplicadoPorDescricao {
+		criterio = "Descrição"
+	}
+
+	texto := fmt.Sprintf("[DUPLICADO] %s '%s' nas linhas %s", criterio, g.Chave, strings.Join(linhas, ", "))
+	if g.Divergente {
+		texto += " — DIVERGENTE em " + strings.Join(g.CamposDivergentes, ", ")
+	}
+	return texto
+}
+
+// Supressao identifica um problema já analisado e aceito: arquivo, código da regra, chave da linha
+// (código do produto, ou "linha N" quando a linha não tem chave) e coluna
+type Supressao struct {
+	Arquivo string `json:"arquivo"`
+	Regra   string `json:"regra"`
+	Chave   string `json:"chave"`
+	Coluna  string `json:"coluna"`
+}
+
+// Comparacao é o resultado da comparação entre duas validações da mesma planilha. Os problemas são
+// casados por regra, chave da linha e coluna — nunca pelo número da linha.
+type Comparacao struct {
+	ArquivoAnterior   string          `json:"arquivoAnterior"`
+	ArquivoAtual      string          `json:"arquivoAtual"`
+	TotalCorrigidos   int             `json:"totalCorrigidos"`
+	TotalNovos        int             `json:"totalNovos"`
+	TotalPersistentes int             `json:"totalPersistentes"`
+	Corrigidos        []ErroValidacao `json:"corrigidos"`   // só na versão anterior
+	Novos             []ErroValidacao `json:"novos"`        // só na versão atual
+	Persistentes      []ErroValidacao `json:"persistentes"` // nas duas (linha e mensagem da versão atual)
+}
+
+// ItemNFe é um item (grupo det) de uma NF-e, com os campos de produto e de ICMS usados na conferência
+type ItemNFe struct {
+	ArquivoXML string `json:"arquivoXML"`
+	ChaveNFe   string `json:"chaveNFe"` // chave de acesso (44 dígitos)
+	NumeroItem int    `json:"numeroItem"`
+	Codigo     string `json:"codigo"` // cProd
+	GTIN       string `json:"gtin"`   // cEAN ("" quando SEM GTIN)
+	Descricao  string `json:"descricao"`
+	NCM        string `json:"ncm"`
+	CEST       string `json:"cest"`
+	Origem     string `json:"origem"`
+	CST        string `json:"cst"`   // regime normal
+	CSOSN      string `json:"csosn"` // Simples Nacional
+}
+
+// DivergenciaNFe aponta um campo em que a linha da planilha e o item da NF-e discordam
+type DivergenciaNFe struct {
+	Linha         int     `json:"linha"`
+	Chave         string  `json:"chave"` // código do produto na planilha
+	Campo         string  `json:"campo"` // nome da coluna na planilha
+	ValorPlanilha string  `json:"valorPlanilha"`
+	ValorNFe      string  `json:"valorNFe"`
+	CasadoPor     string  `json:"casadoPor"` // CasadoPorCodigo ou CasadoPorGTIN
+	Item          ItemNFe `json:"item"`
+}
+
+func (d DivergenciaNFe) String() string {
+	return fmt.Sprintf("Linha %d (%s), %s: planilha '%s' | NF-e '%s' (%s, item %d, casado por %s)",
+		d.Linha, d.Chave, d.Campo, d.ValorPlanilha, d.ValorNFe, d.Item.ArquivoXML, d.Item.NumeroItem, d.CasadoPor)
 }
 
-// ResultadoValidacaoCompleto agrupa os 4 tipos de erros
+// Critérios usados para casar um item da NF-e com uma linha da planilha
+const (
+	CasadoPorCodigo = "CODIGO"
+	CasadoPorGTIN   = "GTIN"
+)
+
+// ConferenciaNFe é o resultado do cruzamento da planilha com um conjunto de XMLs de NF-e
+type ConferenciaNFe struct {
+	NomeArquivo        string           `json:"nomeArquivo"`
+	TotalXMLs          int              `json:"totalXMLs"`
+	TotalItens         int              `json:"totalItens"`
+	TotalConferidos    int              `json:"totalConferidos"`
+	TotalDivergencias  int              `json:"totalDivergencias"`
+	Divergencias       []DivergenciaNFe `json:"divergencias"`
+	SemCorrespondencia []ItemNFe        `json:"semCorrespondencia"` // itens sem linha com o mesmo código ou GTIN
+	XMLsIgnorados      []string         `json:"xmlsIgnorados"`      // arquivos que não são NF-e ou não puderam ser lidos
+}
+
+// Item0200 é um registro 0200 (identificação do item) lido de um arquivo SPED EFD ICMS/IPI
+type Item0200 struct {
+	LinhaArquivo int // linha do registro no arquivo EFD
+	CodItem      string
+	Descricao    string
+	TipoItem     string
+	NCM          string
+	CEST         string
+}
+
+// Baseline é o arquivo de supressões gerado a partir de um resultado anterior
+type Baseline struct {
+	GeradoEm   time.Time   `json:"geradoEm"`
+	Origem     string      `json:"origem"`
+	Supressoes []Supressao `json:"supressoes"`
+}
+
+// ResultadoValidacaoCompleto agrupa os erros por categoria (ver Categorias)
 type ResultadoValidacaoCompleto struct {
-	NomeArquivo    string          `json:"nomeArquivo"`
-	ErrosVazias    []ErroValidacao `json:"errosVazias"`
-	ErrosNCM       []ErroValidacao `json:"errosNCM"`
-	ErrosCSTOrigem []ErroValidacao `json:"errosCSTOrigem"`
-	ErrosCSOSN     []ErroValidacao `json:"errosCSOSN"`
-	ErrosTipoItem  []ErroValidacao `json:"errosTipoItem"`
-	TempoExecucao  time.Duration   `json:"-"`
+	NomeArquivo         string            `json:"nomeArquivo"`
+	ErrosVazias         []ErroValidacao   `json:"errosVazias"`
+	ErrosNCM            []ErroValidacao   `json:"errosNCM"`
+	ErrosCSTOrigem      []ErroValidacao   `json:"errosCSTOrigem"`
+	ErrosCSOSN          []ErroValidacao   `json:"errosCSOSN"`
+	ErrosTipoItem       []ErroValidacao   `json:"errosTipoItem"`
+	ErrosFCI            []ErroValidacao   `json:"errosFCI"`
+	ErrosCBenef         []ErroValidacao   `json:"errosCBenef"`
+	ErrosUnidade        []ErroValidacao   `json:"errosUnidade"`
+	ErrosServico        []ErroValidacao   `json:"errosServico"`
+	ErrosANP            []ErroValidacao   `json:"errosANP"`
+	ErrosReforma        []ErroValidacao   `json:"errosReforma"`
+	ErrosTexto          []ErroValidacao   `json:"errosTexto"`
+	ErrosCelulaNumerica []ErroValidacao   `json:"errosCelulaNumerica"`
+	ErrosEFD            []ErroValidacao   `json:"errosEFD"`
+	Perfil              []PerfilColuna    `json:"perfil"`
+	Cobertura           []CoberturaColuna `json:"cobertura"`
+	Duplicados          []GrupoDuplicado  `json:"duplicados"`
+	AvisosConsistencia  []ErroValidacao   `json:"avisosConsistencia"`
+	Ignorados           []ErroValidacao   `json:"ignorados"`
+	TotalLinhas         int               `json:"-"`
+	TempoExecucao       time.Duration     `json:"-"`
 }
 
 // RespostaValidacaoAPI é a estrutura serializada para a API
 type RespostaValidacaoAPI struct {
-	NomeArquivo    string          `json:"nomeArquivo"`
-	TempoExecucao  string          `json:"processingTime"`
-	TotalErros     int             `json:"totalErros"`
-	ErrosVazias    int             `json:"errosVazias"`
-	ErrosNCM       int             `json:"errosNCM"`
-	ErrosCSTOrigem int             `json:"errosCSTOrigem"`
-	ErrosCSOSN     int             `json:"errosCSOSN"`
-	ErrosTipoItem  int             `json:"errosTipoItem"`
-	Detalhes       []ErroValidacao `json:"detalhes"`
+	NomeArquivo           string            `json:"nomeArquivo"`
+	TempoExecucao         string            `json:"processingTime"`
+	TotalErros            int               `json:"totalErros"`
+	ErrosVazias           int               `json:"errosVazias"`
+	ErrosNCM              int               `json:"errosNCM"`
+	ErrosCSTOrigem        int               `json:"errosCSTOrigem"`
+	ErrosCSOSN            int               `json:"errosCSOSN"`
+	ErrosTipoItem         int               `json:"errosTipoItem"`
+	ErrosPorTipo          map[string]int    `json:"errosPorTipo"`
+	PorSeveridade         map[string]int    `json:"porSeveridade"`
+	Detalhes              []ErroValidacao   `json:"detalhes"`
+	Perfil                []PerfilColuna    `json:"perfil"`
+	Cobertura             []CoberturaColuna `json:"cobertura"`
+	CoberturaObrigatorias float64           `json:"coberturaObrigatorias"` // percentual (0 a 100) das células exigidas preenchidas
+	Duplicados            []GrupoDuplicado  `json:"duplicados"`
+	TotalAvisos           int               `json:"totalAvisos"`
+	Avisos                []ErroValidacao   `json:"avisos"`
+	TotalIgnorados        int               `json:"totalIgnorados"`
+	Ignorados             []ErroValidacao   `json:"ignorados"`
+}
+
+// ErroCampo representa um erro de validação em um campo de um item enviado em JSON
+type ErroCampo struct {
+	Campo      string `json:"campo"`
+	Tipo       string `json:"tipo"`
+	Codigo     string `json:"codigo"`
+	Severidade string `json:"severidade"`
+	Mensagem   string `json:"mensagem"`
+}
+
+// ResultadoItem agrupa os erros de um item, identificado pela sua posição no array enviado.
+// O item é válido quando nenhum problema tem severidade "erro".
+type ResultadoItem struct {
+	Indice int         `json:"indice"`
+	Valido bool        `json:"valido"`
+	Erros  []ErroCampo `json:"erros"`
+}
+
+// RespostaValidacaoLinhasAPI é a resposta do endpoint de validação de itens em JSON
+type RespostaValidacaoLinhasAPI struct {
+	TempoExecucao string          `json:"processingTime"`
+	TotalItens    int             `json:"totalItens"`
+	ItensComErro  int             `json:"itensComErro"`
+	TotalErros    int             `json:"totalErros"`
+	Itens         []ResultadoItem `json:"itens"`
+}
+
+// RespostaErroAPI é o corpo retornado pela API quando a requisição falha
+type RespostaErroAPI struct {
+	Erro     string `json:"erro"`
+	Detalhes string `json:"detalhes,omitempty"`
 }
 
 // MarshalJSON custom para RespostaValidacaoAPI não é necessário — campos são primitivos
@@ -84,26 +434,20 @@ type RespostaValidacaoAPI struct {
 // ToRespostaAPI converte ResultadoValidacaoCompleto para RespostaValidacaoAPI
 func (r ResultadoValidacaoCompleto) ToRespostaAPI() RespostaValidacaoAPI {
 	detalhes := make([]ErroValidacao, 0)
+	errosPorTipo := make(map[string]int)
 
-	for _, e := range r.ErrosVazias {
-		e.Tipo = "VAZIA"
-		detalhes = append(detalhes, e)
-	}
-	for _, e := range r.ErrosNCM {
-		e.Tipo = "NCM"
-		detalhes = append(detalhes, e)
-	}
-	for _, e := range r.ErrosCSTOrigem {
-		e.Tipo = "CST_ORIGEM"
-		detalhes = append(detalhes, e)
-	}
-	for _, e := range r.ErrosCSOSN {
-		e.Tipo = "CSOSN"
-		detalhes = append(detalhes, e)
+	for _, categoria := range r.Categorias() {
+		for _, e := range categoria.Erros {
+			e.Tipo = categoria.Tipo
+			detalhes = append(detalhes, e)
+		}
+		errosPorTipo[categoria.Tipo] = len(categoria.Erros)
 	}
-	for _, e := range r.ErrosTipoItem {
-		e.Tipo = "TIPO_ITEM"
-		detalhes = append(detalhes, e)
+
+	avisos := make([]ErroValidacao, 0, len(r.AvisosConsistencia))
+	for _, a := range r.AvisosConsistencia {
+		a.Tipo = TipoConsistencia
+		avisos = append(avisos, a)
 	}
 
 	// Ordenar por coluna (alfabética) e depois por linha (numérica)
@@ -114,16 +458,52 @@ func (r ResultadoValidacaoCompleto) ToRespostaAPI() RespostaValidacaoAPI {
 		return detalhes[i].Linha < detalhes[j].Linha
 	})
 
+	_, _, cobertura := r.CoberturaObrigatorias()
+
 	return RespostaValidacaoAPI{
-		NomeArquivo:    r.NomeArquivo,
-		TempoExecucao:  r.TempoExecucao.String(),
-		TotalErros:     r.TotalErros(),
-		ErrosVazias:    len(r.ErrosVazias),
-		ErrosNCM:       len(r.ErrosNCM),
-		ErrosCSTOrigem: len(r.ErrosCSTOrigem),
-		ErrosCSOSN:     len(r.ErrosCSOSN),
-		ErrosTipoItem:  len(r.ErrosTipoItem),
-		Detalhes:       detalhes,
+		NomeArquivo:           r.NomeArquivo,
+		TempoExecucao:         r.TempoExecucao.String(),
+		TotalErros:            r.TotalErros(),
+		ErrosVazias:           len(r.ErrosVazias),
+		ErrosNCM:              len(r.ErrosNCM),
+		ErrosCSTOrigem:        len(r.ErrosCSTOrigem),
+		ErrosCSOSN:            len(r.ErrosCSOSN),
+		ErrosTipoItem:         len(r.ErrosTipoItem),
+		ErrosPorTipo:          errosPorTipo,
+		PorSeveridade:         r.PorSeveridade(),
+		Detalhes:              detalhes,
+		Perfil:                r.Perfil,
+		Cobertura:             r.Cobertura,
+		CoberturaObrigatorias: cobertura,
+		Duplicados:            r.Duplicados,
+		TotalAvisos:           len(r.AvisosConsistencia),
+		Avisos:                avisos,
+		TotalIgnorados:        len(r.Ignorados),
+		Ignorados:             r.Ignorados,
+	}
+}
+
+// ToResultadoItem converte o resultado da validação de um único item para ResultadoItem
+func (r ResultadoValidacaoCompleto) ToResultadoItem(indice int) ResultadoItem {
+	erros := make([]ErroCampo, 0)
+	valido := true
+	for _, e := range r.ToRespostaAPI().Detalhes {
+		erros = append(erros, ErroCampo{
+			Campo:      e.NomeColuna,
+			Tipo:       e.Tipo,
+			Codigo:     e.Codigo,
+			Severidade: e.Severidade,
+			Mensagem:   e.Mensagem,
+		})
+		if e.Severidade == SeveridadeErro {
+			valido = false
+		}
+	}
+
+	return ResultadoItem{
+		Indice: indice,
+		Valido: valido,
+		Erros:  erros,
 	}
 }
 
@@ -133,10 +513,86 @@ func (r RespostaValidacaoAPI) MarshalJSON() ([]byte, error) {
 	if r.Detalhes == nil {
 		r.Detalhes = []ErroValidacao{}
 	}
+	if r.ErrosPorTipo == nil {
+		r.ErrosPorTipo = map[string]int{}
+	}
+	if r.PorSeveridade == nil {
+		r.PorSeveridade = map[string]int{}
+	}
+	if r.Perfil == nil {
+		r.Perfil = []PerfilColuna{}
+	}
+	if r.Cobertura == nil {
+		r.Cobertura = []CoberturaColuna{}
+	}
+	if r.Duplicados == nil {
+		r.Duplicados = []GrupoDuplicado{}
+	}
+	if r.Avisos == nil {
+		r.Avisos = []ErroValidacao{}
+	}
+	if r.Ignorados == nil {
+		r.Ignorados = []ErroValidacao{}
+	}
 	return json.Marshal((Alias)(r))
 }
 
-// TotalErros retorna a soma de todos os erros
+// CoberturaObrigatorias soma a cobertura das colunas exigidas: células preenchidas, células exigidas
+// e o percentual (100 quando nenhuma célula é exigida)
+func (r ResultadoValidacaoCompleto) CoberturaObrigatorias() (preenchidas, exigidas int, percentual float64) {
+	for _, c := range r.Cobertura {
+		preenchidas += c.Preenchidas
+		exigidas += c.Exigidas
+	}
+	if exigidas == 0 {
+		return preenchidas, exigidas, 100
+	}
+	return preenchidas, exigidas, math.Round(float64(preenchidas)/float64(exigidas)*1000) / 10
+}
+
+// TotalErros retorna a quantidade de problemas com severidade erro, os únicos que reprovam a planilha.
+// Avisos e informativos ficam de fora; a contagem completa está em PorSeveridade.
 func (r ResultadoValidacaoCompleto) TotalErros() int {
-	return len(r.ErrosVazias) + len(r.ErrosNCM) + len(r.ErrosCSTOrigem) + len(r.ErrosCSOSN) + len(r.ErrosTipoItem)
+	return r.PorSeveridade()[SeveridadeErro]
+}
+
+// TotalProblemas retorna a soma dos problemas das categorias, de qualquer severidade
+func (r ResultadoValidacaoCompleto) TotalProblemas() int {
+	total := 0
+	for _, categoria := range r.Categorias() {
+		total += len(categoria.Erros)
+	}
+	return total
+}
+
+// PorSeveridade conta os problemas (erros das categorias e avisos de consistência) por severidade
+func (r ResultadoValidacaoCompleto) PorSeveridade() map[string]int {
+	contagem := make(map[string]int, len(Severidades))
+	for _, severidade := range Severidades {
+		contagem[severidade] = 0
+	}
+
+	problemas := append([]ErroValidacao(nil), r.AvisosConsistencia...)
+	for _, categoria := range r.Categorias() {
+		problemas = append(problemas, categoria.Erros...)
+	}
+	for _, p := range problemas {
+		severidade := p.Severidade
+		if severidade == "" {
+			severidade = SeveridadeErro
+		}
+		contagem[severidade]++
+	}
+	return contagem
+}
+
+// GruposDivergentes retorna quantos grupos de duplicados discordam nos campos fiscais
+func (r ResultadoValidacaoCompleto) GruposDivergentes() int {
+	total := 0
+	for _, g := range r.Duplicados {
+		if g.Divergente {
+			total++
+		}
+	}
+	return total
 }
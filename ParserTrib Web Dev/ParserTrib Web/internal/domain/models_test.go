@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestTotalErros(t *testing.T) {
+	resultado := ResultadoValidacaoCompleto{
+		ErrosVazias: []ErroValidacao{
+			{Linha: 2, Severidade: SeveridadeErro},
+			{Linha: 3, Severidade: SeveridadeAviso},
+		},
+		ErrosNCM: []ErroValidacao{
+			{Linha: 2}, // sem severidade aplicada conta como erro
+			{Linha: 4, Severidade: SeveridadeInfo},
+		},
+		AvisosConsistencia: []ErroValidacao{
+			{Linha: 5, Severidade: SeveridadeAviso},
+			{Linha: 6, Severidade: SeveridadeErro}, // promovido a erro pelo perfil
+		},
+		Ignorados: []ErroValidacao{{Linha: 7, Severidade: SeveridadeErro}},
+	}
+
+	esperado := map[string]int{SeveridadeErro: 3, SeveridadeAviso: 2, SeveridadeInfo: 1}
+	if porSeveridade := resultado.PorSeveridade(); !maps.Equal(porSeveridade, esperado) {
+		t.Errorf("PorSeveridade = %v, esperado %v", porSeveridade, esperado)
+	}
+	if total := resultado.TotalErros(); total != 3 {
+		t.Errorf("TotalErros = %d, esperado 3", total)
+	}
+	if total := resultado.TotalProblemas(); total != 4 {
+		t.Errorf("TotalProblemas = %d, esperado 4", total)
+	}
+	if resposta := resultado.ToRespostaAPI(); resposta.TotalErros != 3 {
+		t.Errorf("totalErros da API = %d, esperado 3", resposta.TotalErros)
+	}
+}
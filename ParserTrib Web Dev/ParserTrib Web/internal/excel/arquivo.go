@@ -0,0 +1,98 @@
+package excel
+
+import (
+	"ParserTrib/internal/cbenef"
+	"ParserTrib/internal/domain"
+	"ParserTrib/internal/ncm"
+	"ParserTrib/internal/reforma"
+	"fmt"
+	"path/filepath"
+	"time"
+)
+
+// OpcoesValidacao reúne as dependências opcionais da validação de um arquivo
+type OpcoesValidacao struct {
+	IndiceNCM           *ncm.Indice
+	Perfil              *PerfilRegras
+	ColunaChave         string
+	ItensEFD            []domain.Item0200 // registros 0200 para conciliação (opcional)
+	Beneficios          *cbenef.Tabela    // tabela cBenef (opcional)
+	UF                  string            // UF usada na regra do cBenef
+	Unidades            []CodigoTabela    // unidades aceitas (vazio = TabelaUnidades)
+	Servicos            []CodigoTabela    // lista de serviços da LC 116 (vazio = só o formato é validado)
+	PrefixosCombustivel []string          // prefixos de NCM de combustível (vazio = PrefixosCombustivel)
+	ANP                 []CodigoTabela    // tabela de produtos da ANP (vazio = só o formato é validado)
+	Reforma             *reforma.Tabela   // CST IBS/CBS e cClassTrib (regras opcionais do perfil)
+}
+
+// NovoValidatorComOpcoes cria o validador já configurado com as dependências das opções. Os metadados
+// das células dependem do arquivo lido e são informados à parte, com ComMetadados.
+func NovoValidatorComOpcoes(rows [][]string, sheetName string, cabecalhos []string, opcoes OpcoesValidacao) *Validator {
+	return NovoValidator(rows, sheetName, cabecalhos).
+		ComIndiceNCM(opcoes.IndiceNCM).
+		ComPerfilRegras(opcoes.Perfil).
+		ComColunaChave(opcoes.ColunaChave).
+		ComItensEFD(opcoes.ItensEFD).
+		ComBeneficios(opcoes.Beneficios, opcoes.UF).
+		ComUnidades(opcoes.Unidades).
+		ComServicos(opcoes.Servicos).
+		ComCombustiveis(opcoes.PrefixosCombustivel, opcoes.ANP).
+		ComTabelaReforma(opcoes.Reforma)
+}
+
+// ValidarArquivo lê a aba informada e executa todas as validações, sem saída no terminal.
+// Usado quando o resultado é consumido por outra etapa (ex.: comparação entre versões).
+func ValidarArquivo(caminho, sheetName string, opcoes OpcoesValidacao) (domain.ResultadoValidacaoCompleto, error) {
+	var resultado domain.ResultadoValidacaoCompleto
+
+	reader, err := NovoReader(caminho, sheetName)
+	if err != nil {
+		return resultado, fmt.Errorf("erro ao abrir arquivo: %w", err)
+	}
+	defer reader.Close()
+
+	planilha, err := reader.ObterMetadados()
+	if err != nil {
+		return resultado, fmt.Errorf("erro ao ler metadados: %w", err)
+	}
+
+	rows, err := reader.ObterTodasLinhas()
+	if err != nil {
+		return resultado, fmt.Errorf("erro ao ler linhas: %w", err)
+	}
+
+	metadados, err := reader.ObterMetadadosCelulas(rows)
+	if err != nil {
+		return resultado, fmt.Errorf("erro ao ler formato das células: %w", err)
+	}
+
+	inicio := time.Now()
+	resultado = NovoValidatorComOpcoes(rows, sheetName, planilha.Cabecalhos, opcoes).
+		ComMetadados(metadados).
+		ValidarTudo(planilha.TotalLinhas)
+	resultado.NomeArquivo = filepath.Base(caminho)
+	resultado.TempoExecucao = time.Since(inicio)
+
+	return resultado, nil
+}
+
+// LerLinhas devolve os cabeçalhos e todas as linhas da aba informada (a primeira linha é o cabeçalho)
+func LerLinhas(caminho, sheetName string) ([]string, [][]string, error) {
+	reader, err := NovoReader(caminho, sheetName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao abrir arquivo: %w", err)
+	}
+	defer reader.Close()
+
+	planilha, err := reader.ObterMetadados()
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao ler metadados: %w", err)
+	}
+
+	rows, err := reader.ObterTodasLinhas()
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao ler linhas: %w", err)
+	}
+
+	return planilha.Cabecalhos, rows, nil
+}
@@ -0,0 +1,94 @@
+package excel
+
+import (
+	"ParserTrib/internal/cbenef"
+	"ParserTrib/internal/domain"
+	"strings"
+)
+
+// cstsComBeneficio são os CSTs do ICMS (isenção, redução de base, diferimento etc.) em que as UFs
+// da tabela exigem o código de benefício fiscal
+var cstsComBeneficio = map[string]bool{
+	"20": true, "30": true, "40": true, "41": true, "50": true, "51": true, "70": true, "90": true,
+}
+
+// ComBeneficios habilita a verificação do cBenef para a UF informada (sem tabela, sem UF ou com
+// uma UF ausente da tabela, a regra não é aplicada)
+func (v *Validator) ComBeneficios(tabela *cbenef.Tabela, uf string) *Validator {
+	v.beneficios = tabela
+	v.uf = strings.ToUpper(strings.TrimSpace(uf))
+	return v
+}
+
+// validarCBenef confere a coluna cBenef com o CST ICMS da linha: obrigatório para os CSTs de
+// benefício, existente na tabela da UF e permitido para o CST. Sem a coluna cBenef, as linhas que
+// o exigem são apontadas na célula do CST.
+func (v *Validator) validarCBenef() []domain.ErroValidacao {
+	var erros []domain.ErroValidacao
+
+	indiceCST, existe := v.mapaIndices[ColunaCST]
+	if !existe || !v.beneficios.Possui(v.uf) {
+		return erros
+	}
+	indiceCBenef, temCBenef := v.mapaIndices[ColunaCBenef]
+
+	for i := 1; i < len(v.rows); i++ {
+		numLinha := i + 1
+		cst := v.valorCelula(i, indiceCST)
+		// CST com a origem na frente ("040") — só os dois últimos dígitos identificam a tributação
+		if len(cst) == 3 {
+			cst = cst[1:]
+		}
+		exige := cstsComBeneficio[cst]
+
+		if !temCBenef {
+			if exige {
+				erros = append(erros, domain.ErroValidacao{
+					Linha:      numLinha,
+					Coluna:     indiceParaLetra(indiceCST),
+					NomeColuna: ColunaCST,
+					Codigo:     RegraCBenefAusente,
+					Mensagem:   "cBenef OBRIGATÓRIO - " + v.uf + " exige o código de benefício para o CST " + cst + ", mas a planilha não tem a coluna '" + ColunaCBenef + "'",
+				})
+			}
+			continue
+		}
+
+		codigo := strings.ToUpper(v.valorCelula(i, indiceCBenef))
+		if codigo == "" {
+			if exige {
+				erros = append(erros, domain.ErroValidacao{
+					Linha:      numLinha,
+					Coluna:     indiceParaLetra(indiceCBenef),
+					NomeColuna: ColunaCBenef,
+					Codigo:     RegraCBenefAusente,
+					Mensagem:   "cBenef OBRIGATÓRIO - " + v.uf + " exige o código de benefício para o CST " + cst,
+				})
+			}
+			continue
+		}
+
+		beneficio, ok := v.beneficios.Buscar(v.uf, codigo)
+		if !ok {
+			erros = append(erros, domain.ErroValidacao{
+				Linha:      numLinha,
+				Coluna:     indiceParaLetra(indiceCBenef),
+				NomeColuna: ColunaCBenef,
+				Codigo:     RegraCBenefDesconhecido,
+				Mensagem:   "cBenef INVÁLIDO - código não consta na tabela de " + v.uf + " (atual: '" + codigo + "')",
+			})
+			continue
+		}
+		if !beneficio.Permite(cst) {
+			erros = append(erros, domain.ErroValidacao{
+				Linha:      numLinha,
+				Coluna:     indiceParaLetra(indiceCBenef),
+				NomeColuna: ColunaCBenef,
+				Codigo:     RegraCBenefCST,
+				Mensagem:   "cBenef INCOMPATÍVEL COM O CST - " + codigo + " só pode ser usado com os CSTs " + strings.Join(beneficio.CSTs, ", ") + " (CST atual: '" + cst + "')",
+			})
+		}
+	}
+
+	return erros
+}
@@ -0,0 +1,69 @@
+package excel
+
+import (
+	"ParserTrib/internal/cbenef"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidarCBenef(t *testing.T) {
+	caminho := filepath.Join(t.TempDir(), "cbenef.csv")
+	conteudo := "UF;cBenef;CSTs;Descrição\nPR;PR800001;40 41;Isenção\nPR;PR810001;20;Redução de base\n"
+	if err := os.WriteFile(caminho, []byte(conteudo), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tabela, err := cbenef.CarregarCSV(caminho)
+	if err != nil {
+		t.Fatalf("CarregarCSV: %v", err)
+	}
+
+	casos := []struct {
+		nome       string
+		uf         string
+		cabecalhos []string
+		linhas     [][]string
+		esperados  []string
+	}{
+		{
+			nome:       "CST de benefício exige o cBenef",
+			uf:         "PR",
+			cabecalhos: []string{ColunaCodigo, ColunaCST, ColunaCBenef},
+			linhas:     [][]string{{"P1", "40", ""}, {"P2", "00", ""}},
+			esperados:  []string{"2 cBenef CBENEF001"},
+		},
+		{
+			nome:       "CST com a origem na frente e código em minúsculas",
+			uf:         "pr",
+			cabecalhos: []string{ColunaCodigo, ColunaCST, ColunaCBenef},
+			linhas:     [][]string{{"P1", "040", "pr800001"}, {"P2", "120", "PR810001"}},
+		},
+		{
+			nome:       "código fora da tabela e CST incompatível",
+			uf:         "PR",
+			cabecalhos: []string{ColunaCodigo, ColunaCST, ColunaCBenef},
+			linhas:     [][]string{{"P1", "41", "PR899999"}, {"P2", "20", "PR800001"}},
+			esperados:  []string{"2 cBenef CBENEF002", "3 cBenef CBENEF003"},
+		},
+		{
+			nome:       "UF ausente da tabela não é verificada",
+			uf:         "SP",
+			cabecalhos: []string{ColunaCodigo, ColunaCST, ColunaCBenef},
+			linhas:     [][]string{{"P1", "40", ""}},
+		},
+		{
+			nome:       "sem a coluna cBenef o erro vai para o CST",
+			uf:         "PR",
+			cabecalhos: []string{ColunaCodigo, ColunaCST},
+			linhas:     [][]string{{"P1", "40"}, {"P2", "00"}},
+			esperados:  []string{"2 CST ICMS CBENEF001"},
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			v := novoValidatorTeste(caso.cabecalhos, caso.linhas...).ComBeneficios(tabela, caso.uf)
+			conferirErros(t, v.validarCBenef(), caso.esperados)
+		})
+	}
+}
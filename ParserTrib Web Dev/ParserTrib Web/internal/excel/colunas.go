@@ -0,0 +1,35 @@
+package excel
+
+// Nomes das colunas (cabeçalhos) da aba de produtos usados pelas regras fiscais
+const (
+	ColunaCodigo            = "Código"
+	ColunaDescricao         = "Descrição"
+	ColunaNCM               = "NCM"
+	ColunaCSTOrigem         = "CST Origem"
+	ColunaCSOSN             = "CSOSN"
+	ColunaTipoItem          = "Tipo Item"
+	ColunaCEST              = "CEST"
+	ColunaGTIN              = "EAN"     // GTIN — na planilha padrão o cabeçalho é "EAN"
+	ColunaUnidade           = "Unidade" // código da unidade no cadastro do ERP (ex.: "1"), não é validado
+	ColunaFCI               = "FCI"     // número da Ficha de Conteúdo de Importação
+	ColunaCST               = "CST ICMS"
+	ColunaCBenef            = "cBenef"             // código de benefício fiscal da UF
+	ColunaUnidadeComercial  = "Unidade Comercial"  // uCom
+	ColunaUnidadeTributavel = "Unidade Tributável" // uTrib
+	ColunaFatorConversao    = "Fator de Conversão" // quantidade tributável por unidade comercial
+	ColunaCSTIBSCBS         = "CST IBS/CBS"
+	ColunaCClassTrib        = "cClassTrib"     // classificação tributária do IBS/CBS
+	ColunaServico           = "Código Serviço" // item da lista de serviços da LC 116 (Tipo Item 09)
+	ColunaANP               = "Código ANP"     // código de produto da ANP (cProdANP), para combustíveis
+)
+
+// colunasRegras são todas as colunas verificadas pelas regras, na ordem em que entram no modelo
+var colunasRegras = []string{
+	ColunaCodigo, ColunaDescricao, ColunaGTIN, ColunaNCM, ColunaCSOSN, ColunaCSTOrigem, ColunaTipoItem,
+	ColunaCEST, ColunaUnidadeComercial, ColunaUnidadeTributavel, ColunaFatorConversao, ColunaCST,
+	ColunaFCI, ColunaCBenef, ColunaServico, ColunaANP, ColunaCSTIBSCBS, ColunaCClassTrib,
+}
+
+// camposFiscais são os códigos fiscais que produtos iguais ou semelhantes devem compartilhar:
+// comparados entre as linhas de um grupo de duplicados e entre produtos de descrição parecida
+var camposFiscais = []string{ColunaNCM, ColunaCSOSN, ColunaCSTOrigem}
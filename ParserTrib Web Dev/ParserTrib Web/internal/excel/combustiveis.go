@@ -0,0 +1,141 @@
+package excel
+
+import (
+	"ParserTrib/internal/domain"
+	"regexp"
+	"strings"
+)
+
+// PrefixosCombustivel são os prefixos de NCM padrão dos combustíveis, que exigem o código de produto
+// da ANP (cProdANP) na NF-e: álcool etílico (2207), derivados de petróleo (2710), gases (2711) e
+// biodiesel (3826)
+var PrefixosCombustivel = []string{"2207", "2710", "2711", "3826"}
+
+// regexANP valida o código de produto da ANP: 9 dígitos
+var regexANP = regexp.MustCompile(`^\d{9}$`)
+
+// CarregarTabelaANP lê os códigos de produto da ANP de um CSV com as colunas código e descrição
+// (ex.: "320102001;GASOLINA C COMUM"). Sem a tabela, só o formato do código é validado.
+func CarregarTabelaANP(caminho string) ([]CodigoTabela, error) {
+	return lerTabelaCSV(caminho, "produtos ANP", "CPRODANP")
+}
+
+// ComCombustiveis define os prefixos de NCM tratados como combustível (vazio = PrefixosCombustivel)
+// e a tabela de produtos da ANP
+func (v *Validator) ComCombustiveis(prefixos []string, tabela []CodigoTabela) *Validator {
+	if len(prefixos) > 0 {
+		v.prefixosCombustivel = prefixos
+	}
+	if len(tabela) > 0 {
+		v.produtosANP = make(map[string]string, len(tabela))
+		for _, produto := range tabela {
+			v.produtosANP[produto.Codigo] = produto.Descricao
+		}
+	}
+	return v
+}
+
+// ncmCombustivel informa se o NCM começa por um dos prefixos de combustível
+func (v *Validator) ncmCombustivel(ncm string) bool {
+	for _, prefixo := range v.prefixosOuPadrao() {
+		if strings.HasPrefix(ncm, prefixo) {
+			return true
+		}
+	}
+	return false
+}
+
+// validarCombustiveis exige o código ANP nas linhas com NCM de combustível: 9 dígitos, presente na
+// tabela da ANP (quando carregada) e com descrição compatível com a do produto. Código ANP em linha
+// que não é combustível gera aviso. Sem a coluna do código, as linhas que o exigem são apontadas na
+// célula de NCM.
+func (v *Validator) validarCombustiveis() []domain.ErroValidacao {
+	var erros []domain.ErroValidacao
+
+	indiceNCM, existe := v.mapaIndices[ColunaNCM]
+	if !existe {
+		return erros
+	}
+	indiceANP, temANP := v.mapaIndices[ColunaANP]
+	indiceDescricao, temDescricao := v.mapaIndices[ColunaDescricao]
+
+	for i := 1; i < len(v.rows); i++ {
+		numLinha := i + 1
+		ncm := v.valorCelula(i, indiceNCM)
+		combustivel := ncm != "" && v.ncmCombustivel(ncm)
+
+		if !temANP {
+			if combustivel {
+				erros = append(erros, domain.ErroValidacao{
+					Linha:      numLinha,
+					Coluna:     indiceParaLetra(indiceNCM),
+					NomeColuna: ColunaNCM,
+					Codigo:     RegraANPAusente,
+					Mensagem:   "CÓDIGO ANP OBRIGATÓRIO - NCM " + ncm + " é de combustível e exige o código de produto da ANP, mas a planilha não tem a coluna '" + ColunaANP + "'",
+				})
+			}
+			continue
+		}
+
+		codigo := v.valorCelula(i, indiceANP)
+		erro := domain.ErroValidacao{
+			Linha:      numLinha,
+			Coluna:     indiceParaLetra(indiceANP),
+			NomeColuna: ColunaANP,
+		}
+		switch {
+		case combustivel && codigo == "":
+			erro.Codigo = RegraANPAusente
+			erro.Mensagem = "CÓDIGO ANP OBRIGATÓRIO - NCM " + ncm + " é de combustível e exige o código de produto da ANP"
+		case codigo != "" && !combustivel && ncm != "":
+			erro.Codigo = RegraANPDesnecessario
+			erro.Mensagem = "CÓDIGO ANP EM ITEM QUE NÃO É COMBUSTÍVEL - o NCM " + ncm + " não está entre os prefixos de combustível (" + strings.Join(v.prefixosOuPadrao(), ", ") + ")"
+		case codigo != "" && !regexANP.MatchString(codigo):
+			erro.Codigo = RegraANPFormato
+			erro.Mensagem = "CÓDIGO ANP INVÁLIDO - deve conter exatamente 9 dígitos numéricos (atual: '" + codigo + "')"
+		case codigo == "" || v.produtosANP == nil:
+			continue
+		default:
+			descricaoANP, ok := v.produtosANP[codigo]
+			if !ok {
+				erro.Codigo = RegraANPForaTabela
+				erro.Mensagem = "CÓDIGO ANP FORA DA TABELA - não consta na tabela de produtos da ANP (atual: '" + codigo + "')"
+				break
+			}
+			if !temDescricao || descricaoANP == "" {
+				continue
+			}
+			descricao := v.valorCelula(i, indiceDescricao)
+			if descricao == "" || descricoesCompativeis(descricao, descricaoANP) {
+				continue
+			}
+			erro.Coluna = indiceParaLetra(indiceDescricao)
+			erro.NomeColuna = ColunaDescricao
+			erro.Codigo = RegraANPDescricao
+			erro.Mensagem = "DESCRIÇÃO INCOMPATÍVEL COM O CÓDIGO ANP - o código " + codigo + " é '" + descricaoANP + "' (atual: '" + descricao + "')"
+		}
+		erros = append(erros, erro)
+	}
+
+	return erros
+}
+
+// prefixosOuPadrao devolve os prefixos de combustível em uso
+func (v *Validator) prefixosOuPadrao() []string {
+	if v.prefixosCombustivel != nil {
+		return v.prefixosCombustivel
+	}
+	return PrefixosCombustivel
+}
+
+// descricoesCompativeis informa se a descrição do produto tem algum termo (de 3 letras ou mais) em
+// comum com a descrição da ANP ("GASOLINA COMUM ADITIVADA" x "GASOLINA C COMUM")
+func descricoesCompativeis(descricao, descricaoANP string) bool {
+	produto := termosDescricao(descricao).termos
+	for termo := range termosDescricao(descricaoANP).termos {
+		if len(termo) >= 3 && produto[termo] {
+			return true
+		}
+	}
+	return false
+}
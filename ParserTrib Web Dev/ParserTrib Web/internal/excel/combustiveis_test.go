@@ -0,0 +1,69 @@
+package excel
+
+import "testing"
+
+func TestValidarCombustiveis(t *testing.T) {
+	tabela := []CodigoTabela{{Codigo: "320102001", Descricao: "GASOLINA C COMUM"}}
+	cabecalhos := []string{ColunaCodigo, ColunaDescricao, ColunaNCM, ColunaANP}
+
+	casos := []struct {
+		nome       string
+		prefixos   []string
+		tabela     []CodigoTabela
+		cabecalhos []string
+		linhas     [][]string
+		esperados  []string
+	}{
+		{
+			nome:       "combustível com código e descrição compatíveis",
+			tabela:     tabela,
+			cabecalhos: cabecalhos,
+			linhas:     [][]string{{"C1", "Gasolina comum aditivada", "27101259", "320102001"}},
+		},
+		{
+			nome:       "código obrigatório e formato",
+			tabela:     tabela,
+			cabecalhos: cabecalhos,
+			linhas:     [][]string{{"C1", "Diesel S10", "27101921", ""}, {"C2", "Etanol hidratado", "22071000", "12345"}},
+			esperados:  []string{"2 Código ANP ANP001", "3 Código ANP ANP002"},
+		},
+		{
+			nome:       "código fora da tabela e descrição incompatível",
+			tabela:     tabela,
+			cabecalhos: cabecalhos,
+			linhas:     [][]string{{"C1", "Gás liquefeito", "27111910", "999999999"}, {"C2", "Óleo lubrificante", "27101932", "320102001"}},
+			esperados:  []string{"2 Código ANP ANP003", "3 Descrição ANP004"},
+		},
+		{
+			nome:       "sem tabela só o formato é verificado",
+			cabecalhos: cabecalhos,
+			linhas:     [][]string{{"C1", "Óleo lubrificante", "27101932", "999999999"}},
+		},
+		{
+			nome:       "código ANP em item que não é combustível",
+			cabecalhos: cabecalhos,
+			linhas:     [][]string{{"P1", "Caneta", "96081000", "320102001"}},
+			esperados:  []string{"2 Código ANP ANP005"},
+		},
+		{
+			nome:       "prefixos informados substituem os padrão",
+			prefixos:   []string{"9608"},
+			cabecalhos: cabecalhos,
+			linhas:     [][]string{{"P1", "Caneta", "96081000", ""}, {"C1", "Gasolina", "27101259", ""}},
+			esperados:  []string{"2 Código ANP ANP001"},
+		},
+		{
+			nome:       "sem a coluna ANP o erro vai para o NCM",
+			cabecalhos: []string{ColunaCodigo, ColunaNCM},
+			linhas:     [][]string{{"C1", "27101259"}, {"P1", "96081000"}},
+			esperados:  []string{"2 NCM ANP001"},
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			v := novoValidatorTeste(caso.cabecalhos, caso.linhas...).ComCombustiveis(caso.prefixos, caso.tabela)
+			conferirErros(t, v.validarCombustiveis(), caso.esperados)
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package excel
+
+import (
+	"ParserTrib/internal/domain"
+	"ParserTrib/internal/texto"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// termosProduto são os termos da descrição de uma linha; o primeiro (em geral o nome do produto)
+// é usado como chave de bloco na comparação
+type termosProduto struct {
+	primeiro string
+	termos   map[string]bool
+}
+
+// similaridadeMinimaGrupo é o índice de Jaccard mínimo entre as descrições (sem medidas) para agrupar produtos
+const similaridadeMinimaGrupo = 0.75
+
+// verificarConsistencia agrupa as linhas por similaridade da descrição e gera avisos para os produtos
+// cujo código fiscal difere da maioria do grupo ("PARAFUSO SEXTAVADO 10MM" x "PARAFUSO SEXTAVADO 12MM")
+func (v *Validator) verificarConsistencia() []domain.ErroValidacao {
+	var avisos []domain.ErroValidacao
+
+	indiceDescricao, ok := v.mapaIndices[ColunaDescricao]
+	if !ok {
+		return avisos
+	}
+
+	// Termos de cada descrição, sem os que carregam medidas (10MM, 500ML, 2)
+	termos := make(map[int]termosProduto)
+	for i := 1; i < len(v.rows); i++ {
+		if indiceDescricao >= len(v.rows[i]) {
+			continue
+		}
+		if produto := termosDescricao(v.rows[i][indiceDescricao]); len(produto.termos) > 0 {
+			termos[i] = produto
+		}
+	}
+
+	for _, grupo := range agruparSemelhantes(termos) {
+		if len(grupo) < 3 {
+			continue // com dois produtos não há maioria
+		}
+		for _, campo := range camposFiscais {
+			avisos = append(avisos, v.avisosDivergencia(grupo, campo)...)
+		}
+	}
+
+	sort.Slice(avisos, func(i, j int) bool {
+		if avisos[i].Linha != avisos[j].Linha {
+			return avisos[i].Linha < avisos[j].Linha
+		}
+		return avisos[i].Coluna < avisos[j].Coluna
+	})
+	return avisos
+}
+
+// avisosDivergencia compara o campo entre as linhas do grupo e aponta as que fogem do valor majoritário.
+// Só há aviso quando um valor é maioria absoluta entre as linhas preenchidas.
+func (v *Validator) avisosDivergencia(grupo []int, campo string) []domain.ErroValidacao {
+	var avisos []domain.ErroValidacao
+
+	indice, ok := v.mapaIndices[campo]
+	if !ok {
+		return avisos
+	}
+
+	valores := make(map[int]string)
+	frequencias := make(map[string]int)
+	for _, i := range grupo {
+		if indice >= len(v.rows[i]) {
+			continue
+		}
+		valor := strings.TrimSpace(v.rows[i][indice])
+		if valor == "" {
+			continue
+		}
+		valores[i] = valor
+		frequencias[valor]++
+	}
+	if len(frequencias) < 2 {
+		return avisos
+	}
+
+	maioria := maisFrequentes(frequencias, 1)[0]
+	if maioria.Ocorrencias*2 <= len(valores) {
+		return avisos
+	}
+
+	for _, i := range grupo {
+		valor, preenchido := valores[i]
+		if !preenchido || valor == maioria.Valor {
+			continue
+		}
+		avisos = append(avisos, domain.ErroValidacao{
+			Linha:         i + 1,
+			Coluna:        indiceParaLetra(indice),
+			NomeColuna:    campo,
+			Tipo:          domain.TipoConsistencia,
+			Codigo:        RegraConsistencia,
+			ValorSugerido: maioria.Valor,
+			Mensagem: fmt.Sprintf("%s '%s' difere da maioria dos produtos semelhantes (%d de %d usam '%s')",
+				campo, valor, maioria.Ocorrencias, len(valores), maioria.Valor),
+		})
+	}
+
+	return avisos
+}
+
+// agruparSemelhantes forma grupos (componentes conexos) de linhas com descrições semelhantes.
+// Só são comparadas linhas cuja descrição começa pelo mesmo termo, o que mantém o custo baixo
+// em planilhas grandes.
+func agruparSemelhantes(termos map[int]termosProduto) [][]int {
+	linhas := make([]int, 0, len(termos))
+	for i := range termos {
+		linhas = append(linhas, i)
+	}
+	sort.Ints(linhas)
+
+	pai := make(map[int]int, len(linhas))
+	var raiz func(int) int
+	raiz = func(i int) int {
+		for pai[i] != i {
+			pai[i] = pai[pai[i]]
+			i = pai[i]
+		}
+		return i
+	}
+
+	blocos := make(map[string][]int)
+	for _, i := range linhas {
+		pai[i] = i
+		blocos[termos[i].primeiro] = append(blocos[termos[i].primeiro], i)
+	}
+
+	for _, bloco := range blocos {
+		for a := 0; a < len(bloco); a++ {
+			for b := a + 1; b < len(bloco); b++ {
+				if texto.Jaccard(termos[bloco[a]].termos, termos[bloco[b]].termos) >= similaridadeMinimaGrupo {
+					pai[raiz(bloco[b])] = raiz(bloco[a])
+				}
+			}
+		}
+	}
+
+	porRaiz := make(map[int][]int)
+	var raizes []int
+	for _, i := range linhas {
+		r := raiz(i)
+		if _, existe := porRaiz[r]; !existe {
+			raizes = append(raizes, r)
+		}
+		porRaiz[r] = append(porRaiz[r], i)
+	}
+
+	grupos := make([][]int, 0, len(raizes))
+	for _, r := range raizes {
+		grupos = append(grupos, porRaiz[r])
+	}
+	return grupos
+}
+
+// termosDescricao devolve os termos normalizados da descrição, descartando os que contêm dígitos
+func termosDescricao(descricao string) termosProduto {
+	produto := termosProduto{termos: make(map[string]bool)}
+	for _, token := range strings.Fields(texto.NormalizarDescricao(descricao)) {
+		if strings.IndexFunc(token, unicode.IsDigit) >= 0 {
+			continue
+		}
+		if produto.primeiro == "" {
+			produto.primeiro = token
+		}
+		produto.termos[token] = true
+	}
+	return produto
+}
@@ -0,0 +1,140 @@
+package excel
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// resumirAvisos descreve cada aviso como "linha coluna sugestão"
+func resumirAvisos(v *Validator) []string {
+	var resumo []string
+	for _, aviso := range v.verificarConsistencia() {
+		resumo = append(resumo, fmt.Sprintf("%d %s %s", aviso.Linha, aviso.NomeColuna, aviso.ValorSugerido))
+	}
+	return resumo
+}
+
+func TestVerificarConsistencia(t *testing.T) {
+	cabecalhos := []string{ColunaDescricao, ColunaNCM, ColunaCSOSN, ColunaCSTOrigem}
+
+	casos := []struct {
+		nome     string
+		linhas   [][]string
+		esperado []string
+	}{
+		{
+			nome: "medidas não diferenciam os produtos do grupo",
+			linhas: [][]string{
+				{"PARAFUSO SEXTAVADO ACO 10MM", "73181500", "102", "0"},
+				{"PARAFUSO SEXTAVADO ACO 12MM", "73181500", "102", "0"},
+				{"Parafuso Sextavado Aço 8mm", "73181600", "500", "0"},
+			},
+			esperado: []string{"4 NCM 73181500", "4 CSOSN 102"},
+		},
+		{
+			nome: "Jaccard de 0,75 ainda agrupa",
+			linhas: [][]string{
+				{"PARAFUSO SEXTAVADO ACO", "73181500", "102", "0"},
+				{"PARAFUSO SEXTAVADO ACO ZINCADO", "73181500", "102", "0"},
+				{"PARAFUSO SEXTAVADO ACO ZINCADO", "73181600", "102", "0"},
+			},
+			esperado: []string{"4 NCM 73181500"},
+		},
+		{
+			nome: "Jaccard abaixo de 0,75 não agrupa",
+			linhas: [][]string{
+				{"PARAFUSO SEXTAVADO ACO", "73181500", "102", "0"},
+				{"PARAFUSO SEXTAVADO ACO ZINCADO", "73181500", "102", "0"},
+				{"PARAFUSO SEXTAVADO ACO INOX POLIDO", "73181600", "102", "0"},
+			},
+		},
+		{
+			nome: "descrições com outro primeiro termo não são comparadas",
+			linhas: [][]string{
+				{"PARAFUSO SEXTAVADO ACO", "73181500", "102", "0"},
+				{"PARAFUSO SEXTAVADO ACO", "73181500", "102", "0"},
+				{"ACO PARAFUSO SEXTAVADO", "73181600", "102", "0"},
+			},
+		},
+		{
+			nome: "grupo de dois produtos não tem maioria",
+			linhas: [][]string{
+				{"CANETA ESFEROGRAFICA AZUL", "96081000", "102", "0"},
+				{"CANETA ESFEROGRAFICA AZUL", "96082000", "102", "0"},
+			},
+		},
+		{
+			nome: "empate não é maioria absoluta",
+			linhas: [][]string{
+				{"CANETA ESFEROGRAFICA AZUL", "96081000", "102", "0"},
+				{"CANETA ESFEROGRAFICA AZUL", "96081000", "102", "0"},
+				{"CANETA ESFEROGRAFICA AZUL", "96082000", "102", "0"},
+				{"CANETA ESFEROGRAFICA AZUL", "96082000", "102", "0"},
+			},
+		},
+		{
+			nome: "três valores diferentes não têm maioria",
+			linhas: [][]string{
+				{"CANETA ESFEROGRAFICA AZUL", "96081000", "102", "0"},
+				{"CANETA ESFEROGRAFICA AZUL", "96082000", "102", "0"},
+				{"CANETA ESFEROGRAFICA AZUL", "96083000", "102", "0"},
+			},
+		},
+		{
+			nome: "células vazias ficam fora da contagem",
+			linhas: [][]string{
+				{"CANETA ESFEROGRAFICA AZUL", "96081000", "102", "0"},
+				{"CANETA ESFEROGRAFICA AZUL", "96081000", "", "0"},
+				{"CANETA ESFEROGRAFICA AZUL", "", "102", "0"},
+				{"CANETA ESFEROGRAFICA AZUL", "96082000", "102", "0"},
+			},
+			esperado: []string{"5 NCM 96081000"},
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			resumo := resumirAvisos(novoValidatorTeste(cabecalhos, caso.linhas...))
+			if !slices.Equal(resumo, caso.esperado) {
+				t.Errorf("avisos = %q, esperado %q", resumo, caso.esperado)
+			}
+		})
+	}
+
+	t.Run("sem coluna de descrição", func(t *testing.T) {
+		v := novoValidatorTeste([]string{ColunaNCM}, []string{"96081000"}, []string{"96081000"}, []string{"96082000"})
+		if resumo := resumirAvisos(v); resumo != nil {
+			t.Errorf("avisos = %q, esperado nenhum", resumo)
+		}
+	})
+}
+
+// linhasMesmoPrimeiroTermo gera n produtos que caem no mesmo bloco de comparação ("PARAFUSO ..."),
+// com o último fora do NCM majoritário
+func linhasMesmoPrimeiroTermo(n int) [][]string {
+	linhas := make([][]string, n)
+	for i := range linhas {
+		linhas[i] = []string{fmt.Sprintf("PARAFUSO SEXTAVADO ACO %dMM", i+1), "73181500"}
+	}
+	linhas[n-1][1] = "73181600"
+	return linhas
+}
+
+func TestVerificarConsistenciaBlocoGrande(t *testing.T) {
+	const n = 3000
+	v := novoValidatorTeste([]string{ColunaDescricao, ColunaNCM}, linhasMesmoPrimeiroTermo(n)...)
+
+	esperado := []string{fmt.Sprintf("%d NCM 73181500", n+1)}
+	if resumo := resumirAvisos(v); !slices.Equal(resumo, esperado) {
+		t.Errorf("avisos = %q, esperado %q", resumo, esperado)
+	}
+}
+
+func BenchmarkVerificarConsistenciaBlocoGrande(b *testing.B) {
+	v := novoValidatorTeste([]string{ColunaDescricao, ColunaNCM}, linhasMesmoPrimeiroTermo(5000)...)
+	b.ResetTimer()
+	for b.Loop() {
+		v.verificarConsistencia()
+	}
+}
@@ -0,0 +1,212 @@
+package excel
+
+import (
+	"ParserTrib/internal/domain"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// regraCorrecao é uma normalização segura e reversível aplicada às células de uma ou mais colunas.
+// aplicar recebe também o tipo da célula no Excel (CelulaVazia quando desconhecido) e devolve o
+// próprio valor quando a regra não se aplica.
+type regraCorrecao struct {
+	nome    string
+	colunas []string // nil = todas as colunas
+	aplicar func(valor string, tipo domain.TipoCelula) string
+}
+
+var (
+	regexNCMPontuado   = regexp.MustCompile(`^\d[\d.\-\s]*\d$`)
+	regexSeteDigitos   = regexp.MustCompile(`^\d{7}$`)
+	regexUmDigito      = regexp.MustCompile(`^\d$`)
+	regexZerosEsquerda = regexp.MustCompile(`^0+(\d)$`)
+)
+
+// catalogoCorrecoes lista as correções automáticas, na ordem em que são aplicadas a cada célula
+var catalogoCorrecoes = []regraCorrecao{
+	{
+		// Espaços (inclusive não separáveis) no início ou fim das colunas de código e identificadores.
+		// Descrições e colunas de texto livre ("Descrição Longa", "Meta Description") ficam como estão.
+		nome:    "ESPACOS",
+		colunas: colunasSemEspacos(),
+		aplicar: func(valor string, _ domain.TipoCelula) string {
+			return strings.TrimSpace(valor)
+		},
+	},
+	{
+		// "1234.56.78" ou "1234-56-78" → "12345678". Só quando sobram exatamente 8 dígitos:
+		// "3307.20.1" pode ser um NCM truncado e fica para o validador.
+		nome:    "NCM_PONTUACAO",
+		colunas: []string{ColunaNCM},
+		aplicar: func(valor string, _ domain.TipoCelula) string {
+			if !regexNCMPontuado.MatchString(valor) {
+				return valor
+			}
+			digitos := strings.Map(func(r rune) rune {
+				if r >= '0' && r <= '9' {
+					return r
+				}
+				return -1
+			}, valor)
+			if len(digitos) != 8 {
+				return valor
+			}
+			return digitos
+		},
+	},
+	{
+		// NCM gravado como número que perdeu o zero à esquerda (capítulos 01 a 09): "1012100" → "01012100".
+		// Texto com 7 dígitos não é completado: o zero pode estar faltando em qualquer posição.
+		nome:    "NCM_ZERO_ESQUERDA",
+		colunas: []string{ColunaNCM},
+		aplicar: func(valor string, tipo domain.TipoCelula) string {
+			if tipo != domain.CelulaNumero || !regexSeteDigitos.MatchString(valor) {
+				return valor
+			}
+			return "0" + valor
+		},
+	},
+	{
+		// Tipo Item com um dígito: "1" → "01"
+		nome:    "TIPO_ITEM_ZERO_ESQUERDA",
+		colunas: []string{ColunaTipoItem},
+		aplicar: func(valor string, _ domain.TipoCelula) string {
+			if !regexUmDigito.MatchString(valor) || !tiposValidos["0"+valor] {
+				return valor
+			}
+			return "0" + valor
+		},
+	},
+	{
+		// CST Origem com zeros à esquerda: "00" → "0", "03" → "3"
+		nome:    "CST_ORIGEM_ZEROS",
+		colunas: []string{ColunaCSTOrigem},
+		aplicar: func(valor string, _ domain.TipoCelula) string {
+			return regexZerosEsquerda.ReplaceAllString(valor, "$1")
+		},
+	},
+}
+
+// Corretor aplica o catálogo de correções automáticas às linhas da planilha
+type Corretor struct {
+	rows       [][]string
+	cabecalhos []string
+	metadados  [][]domain.MetadadoCelula
+}
+
+// NovoCorretor cria instância do corretor
+func NovoCorretor(rows [][]string, cabecalhos []string) *Corretor {
+	return &Corretor{
+		rows:       rows,
+		cabecalhos: cabecalhos,
+	}
+}
+
+// ComMetadados informa o tipo de cada célula (ver Reader.ObterMetadadosCelulas). Sem metadados,
+// as correções que dependem do tipo (ex.: zero à esquerda do NCM numérico) não são aplicadas.
+func (c *Corretor) ComMetadados(metadados [][]domain.MetadadoCelula) *Corretor {
+	c.metadados = metadados
+	return c
+}
+
+// Corrigir devolve uma cópia das linhas com as correções aplicadas e o registro de cada célula alterada.
+// As linhas originais não são modificadas.
+func (c *Corretor) Corrigir() ([][]string, []domain.Correcao) {
+	var correcoes []domain.Correcao
+
+	corrigidas := make([][]string, len(c.rows))
+	for i, linha := range c.rows {
+		corrigidas[i] = append([]string(nil), linha...)
+	}
+
+	for i := 1; i < len(corrigidas); i++ {
+		linha := corrigidas[i]
+
+		for j := 0; j < len(linha) && j < len(c.cabecalhos); j++ {
+			antes := linha[j]
+			if antes == "" {
+				continue
+			}
+
+			valor := antes
+			tipo := c.tipoCelula(i, j)
+			var regras []string
+			for _, regra := range catalogoCorrecoes {
+				if !regra.seAplicaA(c.cabecalhos[j]) {
+					continue
+				}
+				if novo := regra.aplicar(valor, tipo); novo != valor {
+					valor = novo
+					regras = append(regras, regra.nome)
+				}
+			}
+
+			if valor != antes {
+				linha[j] = valor
+				correcoes = append(correcoes, domain.Correcao{
+					Linha:      i + 1,
+					Coluna:     indiceParaLetra(j),
+					NomeColuna: c.cabecalhos[j],
+					Regras:     strings.Join(regras, ", "),
+					Antes:      antes,
+					Depois:     valor,
+				})
+			}
+		}
+	}
+
+	return corrigidas, correcoes
+}
+
+// tipoCelula devolve o tipo da célula nos metadados, ou CelulaVazia quando não informado
+func (c *Corretor) tipoCelula(i, j int) domain.TipoCelula {
+	if i >= len(c.metadados) || j >= len(c.metadados[i]) {
+		return domain.CelulaVazia
+	}
+	return c.metadados[i][j].Tipo
+}
+
+func (r regraCorrecao) seAplicaA(coluna string) bool {
+	if r.colunas == nil {
+		return true
+	}
+	for _, c := range r.colunas {
+		if c == coluna {
+			return true
+		}
+	}
+	return false
+}
+
+// CaminhoCorrigido devolve onde gravar a planilha corrigida: o caminho da origem com o sufixo
+// "_corrigido" antes da extensão ("produtos.xlsx" → "produtos_corrigido.xlsx")
+func CaminhoCorrigido(origem string) string {
+	extensao := filepath.Ext(origem)
+	return strings.TrimSuffix(origem, extensao) + "_corrigido" + extensao
+}
+
+// SalvarCorrecoes grava uma cópia da planilha de origem em destino com as células corrigidas.
+// As células alteradas passam a ser texto; estilos e demais abas são preservados.
+func SalvarCorrecoes(origem, destino, sheetName string, correcoes []domain.Correcao) error {
+	f, err := excelize.OpenFile(origem)
+	if err != nil {
+		return fmt.Errorf("erro ao abrir arquivo: %w", err)
+	}
+	defer f.Close()
+
+	for _, correcao := range correcoes {
+		celula := fmt.Sprintf("%s%d", correcao.Coluna, correcao.Linha)
+		if err := f.SetCellStr(sheetName, celula, correcao.Depois); err != nil {
+			return fmt.Errorf("erro ao corrigir célula %s: %w", celula, err)
+		}
+	}
+
+	if err := f.SaveAs(destino); err != nil {
+		return fmt.Errorf("erro ao salvar planilha corrigida: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package excel
+
+import (
+	"ParserTrib/internal/domain"
+	"path/filepath"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestCorrigir(t *testing.T) {
+	casos := []struct {
+		nome     string
+		coluna   string
+		valor    string
+		tipo     domain.TipoCelula
+		esperado string
+		regras   string
+	}{
+		{"NCM numérico sem o zero à esquerda", ColunaNCM, "1012100", domain.CelulaNumero, "01012100", "NCM_ZERO_ESQUERDA"},
+		{"NCM texto com 7 dígitos não é completado", ColunaNCM, "1012100", domain.CelulaTexto, "1012100", ""},
+		{"NCM de tipo desconhecido não é completado", ColunaNCM, "1012100", domain.CelulaVazia, "1012100", ""},
+		{"NCM pontuado com 8 dígitos", ColunaNCM, "3307.20.11", domain.CelulaTexto, "33072011", "NCM_PONTUACAO"},
+		{"NCM pontuado com 7 dígitos fica como está", ColunaNCM, "3307.20.1", domain.CelulaTexto, "3307.20.1", ""},
+		{"NCM pontuado numérico com 7 dígitos fica como está", ColunaNCM, "3307.20.1", domain.CelulaNumero, "3307.20.1", ""},
+		{"NCM com hífen", ColunaNCM, "7610-10-00", domain.CelulaTexto, "76101000", "NCM_PONTUACAO"},
+		{"espaços nas pontas", ColunaNCM, " 76101000 ", domain.CelulaTexto, "76101000", "ESPACOS"},
+		{"espaços nas pontas do código do produto", ColunaCodigo, "P1\u00a0", domain.CelulaTexto, "P1", "ESPACOS"},
+		{"espaços na descrição ficam como estão", ColunaDescricao, " Caneta azul ", domain.CelulaTexto, " Caneta azul ", ""},
+		{"texto livre fica como está", "Descrição Longa", "Tinta azul.\n", domain.CelulaTexto, "Tinta azul.\n", ""},
+		{"Meta Description fica como está", "Meta Description", " Caneta ", domain.CelulaTexto, " Caneta ", ""},
+		{"Tipo Item com um dígito", ColunaTipoItem, "4", domain.CelulaNumero, "04", "TIPO_ITEM_ZERO_ESQUERDA"},
+		{"CST Origem com zero à esquerda", ColunaCSTOrigem, "03", domain.CelulaTexto, "3", "CST_ORIGEM_ZEROS"},
+		{"regra de NCM não se aplica a outra coluna", ColunaCodigo, "1012100", domain.CelulaNumero, "1012100", ""},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			rows := [][]string{{caso.coluna}, {caso.valor}}
+			metadados := [][]domain.MetadadoCelula{{}, {{Tipo: caso.tipo}}}
+
+			corrigidas, correcoes := NovoCorretor(rows, rows[0]).ComMetadados(metadados).Corrigir()
+
+			if corrigidas[1][0] != caso.esperado {
+				t.Errorf("valor corrigido = %q, esperado %q", corrigidas[1][0], caso.esperado)
+			}
+			if rows[1][0] != caso.valor {
+				t.Errorf("linha original alterada para %q", rows[1][0])
+			}
+			if caso.regras == "" {
+				if len(correcoes) > 0 {
+					t.Errorf("correções inesperadas: %+v", correcoes)
+				}
+				return
+			}
+			if len(correcoes) != 1 || correcoes[0].Regras != caso.regras || correcoes[0].Linha != 2 || correcoes[0].Coluna != "A" {
+				t.Errorf("correções = %+v, esperado uma correção em A2 pela regra %s", correcoes, caso.regras)
+			}
+		})
+	}
+}
+
+func TestSalvarCorrecoes(t *testing.T) {
+	pasta := t.TempDir()
+	origem := filepath.Join(pasta, "produtos.xlsx")
+	destino := CaminhoCorrigido(origem)
+	if esperado := filepath.Join(pasta, "produtos_corrigido.xlsx"); destino != esperado {
+		t.Fatalf("CaminhoCorrigido = %q, esperado %q", destino, esperado)
+	}
+
+	f := excelize.NewFile()
+	if err := f.SetSheetName("Sheet1", "Produto"); err != nil {
+		t.Fatal(err)
+	}
+	f.SetSheetRow("Produto", "A1", &[]any{ColunaCodigo, ColunaNCM})
+	f.SetSheetRow("Produto", "A2", &[]any{"P1", 1012100})
+	f.SetCellValue("Produto", "A3", "P2")
+	f.NewSheet("Tabelas")
+	f.SetCellValue("Tabelas", "A1", "mantida")
+	if err := f.SaveAs(origem); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	correcoes := []domain.Correcao{{Linha: 2, Coluna: "B", NomeColuna: ColunaNCM, Regras: "NCM_ZERO_ESQUERDA", Antes: "1012100", Depois: "01012100"}}
+	if err := SalvarCorrecoes(origem, destino, "Produto", correcoes); err != nil {
+		t.Fatalf("SalvarCorrecoes: %v", err)
+	}
+
+	corrigido, err := excelize.OpenFile(destino)
+	if err != nil {
+		t.Fatalf("planilha corrigida não foi gravada em %s: %v", destino, err)
+	}
+	defer corrigido.Close()
+
+	if valor, _ := corrigido.GetCellValue("Produto", "B2"); valor != "01012100" {
+		t.Errorf("B2 = %q, esperado %q", valor, "01012100")
+	}
+	if tipo, _ := corrigido.GetCellType("Produto", "B2"); tipo != excelize.CellTypeSharedString && tipo != excelize.CellTypeInlineString {
+		t.Errorf("B2 gravada com tipo %v, esperado texto", tipo)
+	}
+	if valor, _ := corrigido.GetCellValue("Produto", "A3"); valor != "P2" {
+		t.Errorf("A3 = %q, esperado a célula sem correção preservada", valor)
+	}
+	if valor, _ := corrigido.GetCellValue("Tabelas", "A1"); valor != "mantida" {
+		t.Errorf("aba Tabelas não foi preservada (A1 = %q)", valor)
+	}
+
+	original, err := excelize.OpenFile(origem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer original.Close()
+	if valor, _ := original.GetCellValue("Produto", "B2"); valor != "1012100" {
+		t.Errorf("planilha de origem alterada: B2 = %q", valor)
+	}
+
+	if err := SalvarCorrecoes(filepath.Join(pasta, "inexistente.xlsx"), destino, "Produto", correcoes); err == nil {
+		t.Error("origem inexistente: esperado erro")
+	}
+}
+
+func TestCaminhoCorrigido(t *testing.T) {
+	casos := map[string]string{
+		"produtos.xlsx":                "produtos_corrigido.xlsx",
+		"/dados/cadastro.v2.xlsx":      "/dados/cadastro.v2_corrigido.xlsx",
+		"planilhas/Produtos Loja.XLSX": "planilhas/Produtos Loja_corrigido.XLSX",
+	}
+	for origem, esperado := range casos {
+		if obtido := CaminhoCorrigido(origem); obtido != esperado {
+			t.Errorf("CaminhoCorrigido(%q) = %q, esperado %q", origem, obtido, esperado)
+		}
+	}
+}
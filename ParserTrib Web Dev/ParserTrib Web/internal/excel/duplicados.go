@@ -0,0 +1,106 @@
+package excel
+
+import (
+	"ParserTrib/internal/domain"
+	"ParserTrib/internal/texto"
+	"sort"
+	"strings"
+)
+
+// detectarDuplicados agrupa as linhas com o mesmo código de produto ou com a mesma descrição
+// normalizada e marca os grupos cujas linhas discordam em NCM, CSOSN ou CST Origem
+func (v *Validator) detectarDuplicados() []domain.GrupoDuplicado {
+	var grupos []domain.GrupoDuplicado
+
+	grupos = append(grupos, v.agruparPor(ColunaCodigo, domain.DuplicadoPorCodigo, strings.TrimSpace)...)
+	grupos = append(grupos, v.agruparPor(ColunaDescricao, domain.DuplicadoPorDescricao, texto.NormalizarDescricao)...)
+
+	// Divergentes primeiro (caso perigoso), depois pela primeira linha do grupo
+	sort.SliceStable(grupos, func(i, j int) bool {
+		if grupos[i].Divergente != grupos[j].Divergente {
+			return grupos[i].Divergente
+		}
+		return grupos[i].Linhas[0] < grupos[j].Linhas[0]
+	})
+
+	return grupos
+}
+
+// agruparPor (privada para uso interno) forma os grupos de linhas com a mesma chave na coluna informada
+func (v *Validator) agruparPor(coluna, criterio string, chaveDe func(string) string) []domain.GrupoDuplicado {
+	var grupos []domain.GrupoDuplicado
+
+	indice, ok := v.mapaIndices[coluna]
+	if !ok {
+		return grupos
+	}
+
+	linhasPorChave := make(map[string][]int)
+	var ordem []string
+	for i := 1; i < len(v.rows); i++ {
+		if indice >= len(v.rows[i]) {
+			continue
+		}
+		chave := chaveDe(v.rows[i][indice])
+		if chave == "" {
+			continue
+		}
+		if _, existe := linhasPorChave[chave]; !existe {
+			ordem = append(ordem, chave)
+		}
+		linhasPorChave[chave] = append(linhasPorChave[chave], i)
+	}
+
+	for _, chave := range ordem {
+		indicesLinhas := linhasPorChave[chave]
+		if len(indicesLinhas) < 2 {
+			continue
+		}
+
+		grupo := domain.GrupoDuplicado{
+			Criterio:          criterio,
+			Chave:             chave,
+			Linhas:            make([]int, 0, len(indicesLinhas)),
+			CamposDivergentes: make([]string, 0),
+		}
+		for _, i := range indicesLinhas {
+			grupo.Linhas = append(grupo.Linhas, i+1)
+		}
+		for _, campo := range camposFiscais {
+			if v.divergeEm(campo, indicesLinhas) {
+				grupo.CamposDivergentes = append(grupo.CamposDivergentes, campo)
+			}
+		}
+		grupo.Divergente = len(grupo.CamposDivergentes) > 0
+
+		grupos = append(grupos, grupo)
+	}
+
+	return grupos
+}
+
+// divergeEm indica se as linhas têm mais de um valor preenchido na coluna
+// (células vazias já são apontadas pela validação de vazias)
+func (v *Validator) divergeEm(coluna string, indicesLinhas []int) bool {
+	indice, ok := v.mapaIndices[coluna]
+	if !ok {
+		return false
+	}
+
+	primeiro := ""
+	for _, i := range indicesLinhas {
+		if indice >= len(v.rows[i]) {
+			continue
+		}
+		valor := strings.TrimSpace(v.rows[i][indice])
+		if valor == "" {
+			continue
+		}
+		if primeiro == "" {
+			primeiro = valor
+		} else if valor != primeiro {
+			return true
+		}
+	}
+	return false
+}
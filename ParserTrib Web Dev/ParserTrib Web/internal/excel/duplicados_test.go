@@ -0,0 +1,94 @@
+package excel
+
+import (
+	"ParserTrib/internal/domain"
+	"reflect"
+	"testing"
+)
+
+func TestDetectarDuplicados(t *testing.T) {
+	cabecalhos := []string{ColunaCodigo, ColunaDescricao, ColunaNCM, ColunaCSOSN, ColunaCSTOrigem}
+
+	casos := []struct {
+		nome       string
+		cabecalhos []string
+		linhas     [][]string
+		esperado   []domain.GrupoDuplicado
+	}{
+		{
+			nome:       "sem repetição",
+			cabecalhos: cabecalhos,
+			linhas: [][]string{
+				{"1", "CANETA AZUL", "96081000", "102", "0"},
+				{"2", "CANETA PRETA", "96081000", "102", "0"},
+			},
+		},
+		{
+			nome:       "mesmo código com NCM divergente",
+			cabecalhos: cabecalhos,
+			linhas: [][]string{
+				{"10", "CANETA AZUL", "96081000", "102", "0"},
+				{"20", "LAPIS PRETO", "96091000", "102", "0"},
+				{" 10 ", "CANETA AZUL ESCRITA FINA", "96082000", "102", "0"},
+			},
+			esperado: []domain.GrupoDuplicado{
+				{Criterio: domain.DuplicadoPorCodigo, Chave: "10", Linhas: []int{2, 4}, Divergente: true, CamposDivergentes: []string{ColunaNCM}},
+			},
+		},
+		{
+			nome:       "descrição igual após normalizar caixa, acentos, pontuação e unidade",
+			cabecalhos: cabecalhos,
+			linhas: [][]string{
+				{"1", "Sabão em Pó 1kg", "34022000", "102", "0"},
+				{"2", "SABAO  EM PO - 1KG UN", "34022000", "102", "0"},
+				{"3", "DETERGENTE", "34022000", "102", "0"},
+			},
+			esperado: []domain.GrupoDuplicado{
+				{Criterio: domain.DuplicadoPorDescricao, Chave: "sabao em po 1kg", Linhas: []int{2, 3}, CamposDivergentes: []string{}},
+			},
+		},
+		{
+			nome:       "valores vazios não contam como divergência",
+			cabecalhos: cabecalhos,
+			linhas: [][]string{
+				{"7", "COPO", "", "102", "0"},
+				{"7", "COPO DESCARTAVEL", "39241000", "", "0"},
+				{"7", "COPO 200ML", "39241000", "102", ""},
+			},
+			esperado: []domain.GrupoDuplicado{
+				{Criterio: domain.DuplicadoPorCodigo, Chave: "7", Linhas: []int{2, 3, 4}, CamposDivergentes: []string{}},
+			},
+		},
+		{
+			nome:       "grupos divergentes primeiro e depois pela primeira linha",
+			cabecalhos: cabecalhos,
+			linhas: [][]string{
+				{"1", "AGUA MINERAL", "22011000", "102", "0"},
+				{"2", "AGUA MINERAL", "22011000", "102", "0"},
+				{"3", "GUARDANAPO", "48183000", "102", "0"},
+				{"3", "PAPEL TOALHA", "48183000", "500", "1"},
+			},
+			esperado: []domain.GrupoDuplicado{
+				{Criterio: domain.DuplicadoPorCodigo, Chave: "3", Linhas: []int{4, 5}, Divergente: true, CamposDivergentes: []string{ColunaCSOSN, ColunaCSTOrigem}},
+				{Criterio: domain.DuplicadoPorDescricao, Chave: "agua mineral", Linhas: []int{2, 3}, CamposDivergentes: []string{}},
+			},
+		},
+		{
+			nome:       "planilha sem colunas de código e descrição",
+			cabecalhos: []string{ColunaNCM},
+			linhas:     [][]string{{"96081000"}, {"96081000"}},
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			grupos := novoValidatorTeste(caso.cabecalhos, caso.linhas...).detectarDuplicados()
+			if len(grupos) == 0 && len(caso.esperado) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(grupos, caso.esperado) {
+				t.Errorf("grupos = %+v, esperado %+v", grupos, caso.esperado)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package excel
+
+import (
+	"ParserTrib/internal/domain"
+	"fmt"
+	"strings"
+)
+
+// ComItensEFD habilita a conciliação com os registros 0200 de um SPED EFD, casados pela coluna Código
+func (v *Validator) ComItensEFD(itens []domain.Item0200) *Validator {
+	v.itensEFD = itens
+	return v
+}
+
+// camposEFD liga as colunas conferidas ao campo correspondente do registro 0200
+var camposEFD = []struct {
+	coluna string
+	campo  string
+	valor  func(domain.Item0200) string
+}{
+	{ColunaTipoItem, "TIPO_ITEM", func(i domain.Item0200) string { return i.TipoItem }},
+	{ColunaNCM, "COD_NCM", func(i domain.Item0200) string { return i.NCM }},
+	{ColunaCEST, "CEST", func(i domain.Item0200) string { return i.CEST }},
+}
+
+// conciliarEFD aponta os produtos presentes só na planilha ou só no EFD e as diferenças de
+// TIPO_ITEM, COD_NCM e CEST. Itens que existem só no EFD são reportados na linha 0, com o
+// COD_ITEM como chave. Colunas ausentes na planilha não são conferidas.
+func (v *Validator) conciliarEFD() []domain.ErroValidacao {
+	var erros []domain.ErroValidacao
+
+	indiceCodigo, ok := v.mapaIndices[ColunaCodigo]
+	if len(v.itensEFD) == 0 || !ok {
+		return erros
+	}
+
+	porCodigo := make(map[string]domain.Item0200, len(v.itensEFD))
+	for _, item := range v.itensEFD {
+		if _, existe := porCodigo[item.CodItem]; !existe {
+			porCodigo[item.CodItem] = item
+		}
+	}
+
+	naPlanilha := make(map[string]bool)
+	for i := 1; i < len(v.rows); i++ {
+		codigo := v.valorCelula(i, indiceCodigo)
+		if codigo == "" {
+			// Já reportado por validarVazias
+			continue
+		}
+		naPlanilha[codigo] = true
+
+		item, existe := porCodigo[codigo]
+		if !existe {
+			erros = append(erros, domain.ErroValidacao{
+				Linha:      i + 1,
+				Coluna:     indiceParaLetra(indiceCodigo),
+				NomeColuna: ColunaCodigo,
+				Codigo:     RegraEFDAusenteNoEFD,
+				Mensagem:   "PRODUTO SEM REGISTRO 0200 NO EFD (código: '" + codigo + "')",
+			})
+			continue
+		}
+
+		for _, campo := range camposEFD {
+			indice, ok := v.mapaIndices[campo.coluna]
+			if !ok {
+				continue
+			}
+			valor := v.valorCelula(i, indice)
+			if strings.ReplaceAll(valor, ".", "") == strings.ReplaceAll(campo.valor(item), ".", "") {
+				continue
+			}
+			erros = append(erros, domain.ErroValidacao{
+				Linha:      i + 1,
+				Coluna:     indiceParaLetra(indice),
+				NomeColuna: campo.coluna,
+				Codigo:     RegraEFDDivergente,
+				Mensagem: fmt.Sprintf("%s DIFERENTE DO EFD - planilha '%s', registro 0200 '%s' (linha %d do EFD)",
+					campo.campo, valor, campo.valor(item), item.LinhaArquivo),
+			})
+		}
+	}
+
+	for _, item := range v.itensEFD {
+		if naPlanilha[item.CodItem] {
+			continue
+		}
+		naPlanilha[item.CodItem] = true // repetições no EFD são reportadas uma vez
+		erros = append(erros, domain.ErroValidacao{
+			Coluna:     indiceParaLetra(indiceCodigo),
+			NomeColuna: ColunaCodigo,
+			Codigo:     RegraEFDAusenteNaPlanilha,
+			Chave:      item.CodItem,
+			Mensagem: fmt.Sprintf("REGISTRO 0200 SEM PRODUTO NA PLANILHA - COD_ITEM '%s' (%s), linha %d do EFD",
+				item.CodItem, item.Descricao, item.LinhaArquivo),
+		})
+	}
+
+	return erros
+}
+
+// valorCelula devolve o conteúdo da célula sem espaços nas pontas ("" se a linha for mais curta)
+func (v *Validator) valorCelula(linha, indice int) string {
+	if indice >= len(v.rows[linha]) {
+		return ""
+	}
+	return strings.TrimSpace(v.rows[linha][indice])
+}
@@ -0,0 +1,54 @@
+package excel
+
+import (
+	"ParserTrib/internal/domain"
+	"testing"
+)
+
+func TestConciliarEFD(t *testing.T) {
+	itens := []domain.Item0200{
+		{LinhaArquivo: 5, CodItem: "P1", TipoItem: "00", NCM: "96081000"},
+		{LinhaArquivo: 6, CodItem: "P3", TipoItem: "00", NCM: "96091000"},
+		{LinhaArquivo: 7, CodItem: "P4", TipoItem: "04", NCM: "96081000", CEST: "1234567"},
+		{LinhaArquivo: 8, CodItem: "P3", TipoItem: "00", NCM: "96091000"},
+	}
+
+	casos := []struct {
+		nome       string
+		itens      []domain.Item0200
+		cabecalhos []string
+		linhas     [][]string
+		esperados  []string
+	}{
+		{
+			nome:       "produtos de um lado só e campos divergentes",
+			itens:      itens,
+			cabecalhos: []string{ColunaCodigo, ColunaTipoItem, ColunaNCM, ColunaCEST},
+			linhas: [][]string{
+				{"P1", "00", "9608.10.00", ""},
+				{"P2", "00", "96081000", ""},
+				{"P4", "00", "96081000", "1234567"},
+				{"", "00", "", ""},
+			},
+			esperados: []string{"3 Código EFD001", "4 Tipo Item EFD003", "0 Código EFD002"},
+		},
+		{
+			nome:       "colunas ausentes não são conferidas",
+			itens:      itens[:1],
+			cabecalhos: []string{ColunaCodigo},
+			linhas:     [][]string{{"P1"}},
+		},
+		{
+			nome:       "sem itens do EFD",
+			cabecalhos: []string{ColunaCodigo},
+			linhas:     [][]string{{"P1"}},
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			v := novoValidatorTeste(caso.cabecalhos, caso.linhas...).ComItensEFD(caso.itens)
+			conferirErros(t, v.conciliarEFD(), caso.esperados)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package excel
+
+import (
+	"ParserTrib/internal/domain"
+	"regexp"
+)
+
+// origensComFCI são as origens (CST Origem) com conteúdo de importação: a NF-e exige o número da
+// Ficha de Conteúdo de Importação (nFCI) nesses itens
+var origensComFCI = map[string]bool{"3": true, "5": true, "8": true}
+
+// regexFCI valida o número da FCI no formato do leiaute da NF-e: UUID com 36 caracteres, hexadecimal maiúsculo
+var regexFCI = regexp.MustCompile(`^[0-9A-F]{8}-[0-9A-F]{4}-[0-9A-F]{4}-[0-9A-F]{4}-[0-9A-F]{12}$`)
+
+// validarFCI verifica a coluna FCI conforme a CST Origem da linha: obrigatória e no formato UUID
+// para as origens 3, 5 e 8; preenchida nas demais origens gera aviso. Sem a coluna FCI, as linhas
+// que a exigem são apontadas na célula de CST Origem.
+func (v *Validator) validarFCI() []domain.ErroValidacao {
+	var erros []domain.ErroValidacao
+
+	indiceCST, existe := v.mapaIndices[ColunaCSTOrigem]
+	if !existe {
+		return erros
+	}
+	indiceFCI, temFCI := v.mapaIndices[ColunaFCI]
+
+	for i := 1; i < len(v.rows); i++ {
+		numLinha := i + 1
+		origem := v.valorCelula(i, indiceCST)
+		exige := origensComFCI[origem]
+
+		if !temFCI {
+			if exige {
+				erros = append(erros, domain.ErroValidacao{
+					Linha:      numLinha,
+					Coluna:     indiceParaLetra(indiceCST),
+					NomeColuna: ColunaCSTOrigem,
+					Codigo:     RegraFCIAusente,
+					Mensagem:   "FCI OBRIGATÓRIA - CST Origem " + origem + " exige o número da FCI, mas a planilha não tem a coluna '" + ColunaFCI + "'",
+				})
+			}
+			continue
+		}
+
+		fci := v.valorCelula(i, indiceFCI)
+		switch {
+		case exige && fci == "":
+			erros = append(erros, domain.ErroValidacao{
+				Linha:      numLinha,
+				Coluna:     indiceParaLetra(indiceFCI),
+				NomeColuna: ColunaFCI,
+				Codigo:     RegraFCIAusente,
+				Mensagem:   "FCI OBRIGATÓRIA - CST Origem " + origem + " (conteúdo de importação) exige o número da FCI",
+			})
+		case fci != "" && !regexFCI.MatchString(fci):
+			erros = append(erros, domain.ErroValidacao{
+				Linha:      numLinha,
+				Coluna:     indiceParaLetra(indiceFCI),
+				NomeColuna: ColunaFCI,
+				Codigo:     RegraFCIFormato,
+				Mensagem:   "FCI INVÁLIDA - deve ter 36 caracteres no formato XXXXXXXX-XXXX-XXXX-XXXX-XXXXXXXXXXXX, hexadecimal maiúsculo (atual: '" + fci + "')",
+			})
+		case fci != "" && !exige && origem != "":
+			erros = append(erros, domain.ErroValidacao{
+				Linha:      numLinha,
+				Coluna:     indiceParaLetra(indiceFCI),
+				NomeColuna: ColunaFCI,
+				Codigo:     RegraFCIDesnecessaria,
+				Mensagem:   "FCI PREENCHIDA SEM NECESSIDADE - só as origens 3, 5 e 8 exigem FCI (CST Origem atual: " + origem + ")",
+			})
+		}
+	}
+
+	return erros
+}
@@ -0,0 +1,50 @@
+package excel
+
+import "testing"
+
+func TestValidarFCI(t *testing.T) {
+	const fci = "B01F70AF-10BF-4B1F-848C-65FF57F616FE"
+
+	casos := []struct {
+		nome       string
+		cabecalhos []string
+		linhas     [][]string
+		esperados  []string
+	}{
+		{
+			nome:       "origem com conteúdo de importação exige a FCI",
+			cabecalhos: []string{ColunaCodigo, ColunaCSTOrigem, ColunaFCI},
+			linhas:     [][]string{{"P1", "3", ""}, {"P2", "5", fci}},
+			esperados:  []string{"2 FCI FCI001"},
+		},
+		{
+			nome:       "formato em minúsculas é recusado",
+			cabecalhos: []string{ColunaCodigo, ColunaCSTOrigem, ColunaFCI},
+			linhas:     [][]string{{"P1", "8", "b01f70af-10bf-4b1f-848c-65ff57f616fe"}, {"P2", "8", "123"}},
+			esperados:  []string{"2 FCI FCI002", "3 FCI FCI002"},
+		},
+		{
+			nome:       "FCI em origem nacional gera aviso",
+			cabecalhos: []string{ColunaCodigo, ColunaCSTOrigem, ColunaFCI},
+			linhas:     [][]string{{"P1", "0", fci}, {"P2", "", fci}},
+			esperados:  []string{"2 FCI FCI003"},
+		},
+		{
+			nome:       "sem a coluna FCI o erro vai para a CST Origem",
+			cabecalhos: []string{ColunaCodigo, ColunaCSTOrigem},
+			linhas:     [][]string{{"P1", "3"}, {"P2", "0"}},
+			esperados:  []string{"2 CST Origem FCI001"},
+		},
+		{
+			nome:       "sem a coluna CST Origem nada é verificado",
+			cabecalhos: []string{ColunaCodigo, ColunaFCI},
+			linhas:     [][]string{{"P1", "123"}},
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			conferirErros(t, novoValidatorTeste(caso.cabecalhos, caso.linhas...).validarFCI(), caso.esperados)
+		})
+	}
+}
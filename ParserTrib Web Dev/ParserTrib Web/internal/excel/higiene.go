@@ -0,0 +1,194 @@
+package excel
+
+import (
+	"ParserTrib/internal/domain"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// regraTexto define os limites de texto de uma coluna, conforme o leiaute da NF-e e do SPED
+type regraTexto struct {
+	coluna    string
+	tamanho   int    // máximo de caracteres (0 = sem limite)
+	proibidos string // caracteres não aceitos na coluna
+	codigo    bool   // célula de código: espaços no início ou no fim são erro
+}
+
+// regrasTexto lista as colunas que vão para a NF-e ou para o SPED e os seus limites. O "|" é o
+// separador do SPED e não pode aparecer no conteúdo; as colunas de código não admitem espaços nas
+// pontas (o ERP grava o valor como está). Colunas de texto livre do site ("Descrição Longa",
+// "Meta Description") ficam de fora: quebras de linha nelas são legítimas.
+var regrasTexto = []regraTexto{
+	{ColunaDescricao, 120, "|", false},      // xProd
+	{ColunaCodigo, 60, "|", true},           // cProd
+	{ColunaUnidadeComercial, 6, "| ", true}, // uCom
+	{ColunaUnidadeTributavel, 6, "| ", true},
+	{ColunaNCM, 0, "|", true},
+	{ColunaCEST, 0, "|", true},
+	{ColunaGTIN, 14, "|", true}, // aceita "SEM GTIN"
+	{ColunaTipoItem, 0, "|", true},
+	{ColunaCSTOrigem, 0, "|", true},
+	{ColunaCSOSN, 0, "|", true},
+	{ColunaCST, 0, "|", true},
+	{ColunaFCI, 36, "| ", true},
+	{ColunaCBenef, 10, "| ", true},
+	{ColunaServico, 5, "| ", true},
+	{ColunaANP, 9, "| ", true},
+	{ColunaCSTIBSCBS, 3, "| ", true},
+	{ColunaCClassTrib, 6, "| ", true},
+}
+
+// colunasSemEspacos devolve as colunas de regrasTexto marcadas como código, que não admitem espaços nas pontas
+func colunasSemEspacos() []string {
+	var colunas []string
+	for _, regra := range regrasTexto {
+		if regra.codigo {
+			colunas = append(colunas, regra.coluna)
+		}
+	}
+	return colunas
+}
+
+// regexMojibake reconhece texto UTF-8 lido como Latin-1/Windows-1252: "Ã§" no lugar de "ç", "Ã©" no de "é"
+var regexMojibake = regexp.MustCompile(`[ÃÂ][\x{0080}-\x{00BF}]`)
+
+// espacosNaoSeparaveis são os espaços que parecem comuns mas quebram buscas e comparações
+var espacosNaoSeparaveis = map[rune]bool{'\u00a0': true, '\u2007': true, '\u202f': true}
+
+// validarTexto verifica a higiene do texto das colunas de regrasTexto: caracteres de controle, espaços
+// não separáveis, acentuação corrompida, tamanho máximo, caracteres proibidos e espaços nas pontas.
+// Cada problema traz o valor normalizado como sugestão.
+func (v *Validator) validarTexto() []domain.ErroValidacao {
+	var erros []domain.ErroValidacao
+
+	regras := make(map[string]regraTexto, len(regrasTexto))
+	for _, regra := range regrasTexto {
+		regras[regra.coluna] = regra
+	}
+
+	for i := 1; i < len(v.rows); i++ {
+		for j, valor := range v.rows[i] {
+			if j >= len(v.cabecalhos) || strings.TrimSpace(valor) == "" {
+				continue
+			}
+			regra, temRegra := regras[v.cabecalhos[j]]
+			if !temRegra {
+				continue
+			}
+
+			problema := func(codigo, mensagem string) {
+				sugestao := normalizarTexto(valor, regra)
+				if sugestao != "" {
+					mensagem += " (sugestão: '" + sugestao + "')"
+				}
+				erros = append(erros, domain.ErroValidacao{
+					Linha:         i + 1,
+					Coluna:        indiceParaLetra(j),
+					NomeColuna:    regra.coluna,
+					Codigo:        codigo,
+					ValorSugerido: sugestao,
+					Mensagem:      mensagem,
+				})
+			}
+
+			if strings.IndexFunc(valor, caractereControle) >= 0 {
+				problema(RegraTextoControle, "CARACTERE DE CONTROLE - quebras de linha, tabulações e caracteres invisíveis são rejeitados na NF-e")
+			}
+			if strings.IndexFunc(valor, func(r rune) bool { return espacosNaoSeparaveis[r] }) >= 0 {
+				problema(RegraTextoEspacoEspecial, "ESPAÇO NÃO SEPARÁVEL - parece um espaço comum, mas impede buscas e comparações")
+			}
+			if regexMojibake.MatchString(valor) {
+				problema(RegraTextoAcentuacao, "ACENTUAÇÃO CORROMPIDA - texto UTF-8 gravado como Latin-1 (ex.: 'Ã§' no lugar de 'ç')")
+			}
+			if regra.codigo && valor != strings.TrimSpace(valor) {
+				problema(RegraTextoEspacosPontas, "ESPAÇOS NO INÍCIO OU NO FIM DO CÓDIGO - o valor é gravado com os espaços e deixa de casar com o cadastro")
+			}
+			if proibidos := caracteresProibidos(valor, regra.proibidos); proibidos != "" {
+				problema(RegraTextoProibido, "CARACTERE NÃO PERMITIDO NA COLUNA - "+proibidos)
+			}
+			if tamanho := utf8.RuneCountInString(strings.TrimSpace(valor)); regra.tamanho > 0 && tamanho > regra.tamanho {
+				problema(RegraTextoTamanho, fmt.Sprintf("TEXTO ACIMA DO LIMITE - máximo de %d caracteres (atual: %d)", regra.tamanho, tamanho))
+			}
+		}
+	}
+
+	return erros
+}
+
+// caractereControle reconhece caracteres de controle e invisíveis (largura zero, BOM)
+func caractereControle(r rune) bool {
+	return unicode.IsControl(r) || r == '\u200b' || r == '\u200c' || r == '\u200d' || r == '\ufeff'
+}
+
+// caracteresProibidos descreve os caracteres proibidos presentes no valor (ignorando as pontas,
+// já tratadas pela regra de espaços)
+func caracteresProibidos(valor, proibidos string) string {
+	var encontrados []string
+	miolo := strings.TrimSpace(valor)
+	for _, r := range proibidos {
+		if !strings.ContainsRune(miolo, r) {
+			continue
+		}
+		if r == ' ' {
+			encontrados = append(encontrados, "espaço")
+		} else {
+			encontrados = append(encontrados, "'"+string(r)+"'")
+		}
+	}
+	return strings.Join(encontrados, ", ")
+}
+
+// normalizarTexto aplica todas as correções de higiene ao valor: recupera a acentuação, troca
+// controles e espaços especiais por espaço comum, remove os caracteres proibidos, junta espaços
+// repetidos, apara as pontas e corta no limite da coluna (na última palavra inteira, se possível)
+func normalizarTexto(valor string, regra regraTexto) string {
+	if regexMojibake.MatchString(valor) {
+		valor = recuperarAcentuacao(valor)
+	}
+
+	var sb strings.Builder
+	for _, r := range valor {
+		switch {
+		case r == '\t' || r == '\n' || r == '\r' || espacosNaoSeparaveis[r]:
+			sb.WriteRune(' ')
+		case caractereControle(r):
+			continue
+		case r != ' ' && strings.ContainsRune(regra.proibidos, r):
+			continue
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	valor = strings.Join(strings.Fields(sb.String()), " ")
+	if strings.ContainsRune(regra.proibidos, ' ') {
+		valor = strings.ReplaceAll(valor, " ", "")
+	}
+
+	if regra.tamanho > 0 && utf8.RuneCountInString(valor) > regra.tamanho {
+		cortado := string([]rune(valor)[:regra.tamanho])
+		if espaco := strings.LastIndex(cortado, " "); espaco > 0 && !regra.codigo {
+			cortado = cortado[:espaco]
+		}
+		valor = strings.TrimSpace(cortado)
+	}
+	return valor
+}
+
+// recuperarAcentuacao desfaz a leitura de UTF-8 como Latin-1 ("AÃ§Ãºcar" → "Açúcar"). Se o texto
+// não puder ser recuperado com segurança, devolve o original.
+func recuperarAcentuacao(valor string) string {
+	bytes := make([]byte, 0, len(valor))
+	for _, r := range valor {
+		if r > 0xFF {
+			return valor
+		}
+		bytes = append(bytes, byte(r))
+	}
+	if !utf8.Valid(bytes) {
+		return valor
+	}
+	return string(bytes)
+}
@@ -0,0 +1,70 @@
+package excel
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidarTexto(t *testing.T) {
+	cabecalhos := []string{ColunaCodigo, ColunaDescricao, "Descrição Longa", ColunaUnidadeComercial}
+
+	casos := []struct {
+		nome      string
+		linha     []string
+		esperados []string
+		sugestoes []string
+	}{
+		{
+			nome:  "texto limpo e quebra de linha em coluna de texto livre",
+			linha: []string{"P1", "Caneta azul", "Tinta azul.\nCorpo transparente.", "UN"},
+		},
+		{
+			nome:      "espaços nas pontas do código",
+			linha:     []string{" P1 ", "Caneta", "", "UN"},
+			esperados: []string{"2 Código TXT005"},
+			sugestoes: []string{"P1"},
+		},
+		{
+			nome:      "acentuação corrompida",
+			linha:     []string{"P1", "AÃ§Ãºcar cristal", "", "KG"},
+			esperados: []string{"2 Descrição TXT004"},
+			sugestoes: []string{"Açúcar cristal"},
+		},
+		{
+			nome:      "tabulação e espaço na unidade",
+			linha:     []string{"P1", "Caneta\tazul", "", "U N"},
+			esperados: []string{"2 Descrição TXT002", "2 Unidade Comercial TXT006"},
+			sugestoes: []string{"Caneta azul", "UN"},
+		},
+		{
+			nome:      "espaço não separável",
+			linha:     []string{"P1", "Caneta\u00a0azul", "", "UN"},
+			esperados: []string{"2 Descrição TXT003"},
+			sugestoes: []string{"Caneta azul"},
+		},
+		{
+			nome:      "separador do SPED",
+			linha:     []string{"P1", "Caneta | azul", "", "UN"},
+			esperados: []string{"2 Descrição TXT006"},
+			sugestoes: []string{"Caneta azul"},
+		},
+		{
+			nome:      "descrição acima do limite é cortada na última palavra",
+			linha:     []string{"P1", strings.Repeat("caneta ", 20), "", "UN"},
+			esperados: []string{"2 Descrição TXT001"},
+			sugestoes: []string{strings.TrimSpace(strings.Repeat("caneta ", 17))},
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			erros := novoValidatorTeste(cabecalhos, caso.linha).validarTexto()
+			conferirErros(t, erros, caso.esperados)
+			for i, e := range erros {
+				if i < len(caso.sugestoes) && e.ValorSugerido != caso.sugestoes[i] {
+					t.Errorf("sugestão de %s = %q, esperado %q", e.Codigo, e.ValorSugerido, caso.sugestoes[i])
+				}
+			}
+		})
+	}
+}
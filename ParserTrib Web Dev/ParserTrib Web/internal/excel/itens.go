@@ -0,0 +1,25 @@
+package excel
+
+import (
+	"ParserTrib/internal/domain"
+	"sort"
+)
+
+// ValidarItem valida um único produto enviado como objeto chave/valor (ex.: integração com ERP),
+// aplicando as mesmas regras do Validator. Apenas os campos presentes no item são verificados, exceto
+// as exigências condicionais (ex.: FCI para CST Origem 3, 5 e 8).
+func ValidarItem(item map[string]string, sheetName string, opcoes OpcoesValidacao) domain.ResultadoValidacaoCompleto {
+	cabecalhos := make([]string, 0, len(item))
+	for campo := range item {
+		cabecalhos = append(cabecalhos, campo)
+	}
+	sort.Strings(cabecalhos)
+
+	valores := make([]string, len(cabecalhos))
+	for i, campo := range cabecalhos {
+		valores[i] = item[campo]
+	}
+
+	rows := [][]string{cabecalhos, valores}
+	return NovoValidatorComOpcoes(rows, sheetName, cabecalhos, opcoes).ValidarTudo(1)
+}
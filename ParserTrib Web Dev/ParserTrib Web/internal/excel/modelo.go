@@ -0,0 +1,275 @@
+package excel
+
+import (
+	"ParserTrib/internal/domain"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Parâmetros da planilha modelo
+const (
+	sheetTabelas     = "Tabelas" // aba oculta com as tabelas de códigos
+	linhasModelo     = 5000      // linhas cobertas pelas listas suspensas e pela formatação
+	autorComentarios = "ParserTrib"
+	larguraColuna    = 18
+)
+
+// campoModelo descreve uma coluna da planilha modelo
+type campoModelo struct {
+	nome      string
+	descricao string
+	codigo    bool // coluna de código: formatada como texto para preservar zeros à esquerda
+}
+
+// camposLayout são as colunas do layout de importação do ERP, na ordem em que aparecem no modelo
+var camposLayout = []campoModelo{
+	{ColunaCodigo, "Código interno do produto. Obrigatório para produtos pai.", true},
+	{ColunaDescricao, "Descrição do produto como deve sair na nota fiscal (até 120 caracteres).", false},
+	{"Referência", "Referência do fabricante ou código alternativo.", true},
+	{"Código Pai", "Código do produto pai, preenchido apenas nas variações.", true},
+	{"Variação", "Nome do primeiro atributo de grade (ex.: Cor).", false},
+	{"Nome Grade X", "Valor do primeiro atributo de grade (ex.: Branco).", false},
+	{"SubVariação", "Nome do segundo atributo de grade (ex.: Tamanho).", false},
+	{"Nome Grade Y", "Valor do segundo atributo de grade (ex.: P).", false},
+	{"Fabricante", "Código do fabricante no cadastro do ERP.", true},
+	{"Categoria", "Código da categoria no cadastro do ERP.", true},
+	{ColunaGTIN, "GTIN/EAN do produto (8, 12, 13 ou 14 dígitos) ou SEM GTIN. Digite como texto.", true},
+	{ColunaUnidade, "Código da unidade no cadastro do ERP.", true},
+	{"Controla Estoque ?", "S ou N.", false},
+	{"Comercialização", "Forma de comercialização do produto.", false},
+	{"Preço prevalece do Pai ?", "S ou N — variações herdam o preço do produto pai.", false},
+	{"Promoção prevalece do Pai ?", "S ou N — variações herdam a promoção do produto pai.", false},
+	{"Preço Fictício Site", "Preço 'de' exibido no site.", false},
+	{"Preço Site", "Preço de venda no site.", false},
+	{"Preço Fictício", "Preço 'de' exibido na loja.", false},
+	{"Preço", "Preço de venda.", false},
+	{"Custo", "Custo do produto.", false},
+	{"Peso", "Peso em kg.", false},
+	{"LARGURA", "Largura em cm.", false},
+	{"ALTURA", "Altura em cm.", false},
+	{"PROFUNDIDADE", "Profundidade em cm.", false},
+	{"Percentual Site", "Percentual aplicado ao preço do site.", false},
+	{"Ativo", "S ou N.", false},
+	{ColunaNCM, "NCM com exatamente 8 dígitos, sem pontos (ex.: 76101000).", true},
+	{ColunaCSOSN, "CSOSN do Simples Nacional. Escolha um valor da lista.", true},
+	{"CFOP", "CFOP padrão de saída (4 dígitos).", true},
+	{ColunaCSTOrigem, "Origem da mercadoria (0 a 8). Escolha um valor da lista.", true},
+	{"Descrição Longa", "Descrição completa para o site.", false},
+	{"Meta title", "Título para SEO.", false},
+	{"Meta Description", "Descrição para SEO.", false},
+	{ColunaTipoItem, "Tipo do item conforme SPED (00 a 10 ou 99). Escolha um valor da lista.", true},
+}
+
+// camposRegras descrevem as demais colunas verificadas pelas regras, acrescentadas ao modelo depois
+// do layout do ERP, na ordem de colunasRegras
+var camposRegras = map[string]campoModelo{
+	ColunaCEST:              {ColunaCEST, "CEST com 7 dígitos, para produtos sujeitos à substituição tributária.", true},
+	ColunaUnidadeComercial:  {ColunaUnidadeComercial, "Unidade comercial da NF-e (uCom), conforme a tabela de unidades (ex.: UN, KG, CX).", true},
+	ColunaUnidadeTributavel: {ColunaUnidadeTributavel, "Unidade tributável da NF-e (uTrib), conforme a tabela de unidades.", true},
+	ColunaFatorConversao:    {ColunaFatorConversao, "Quantidade tributável por unidade comercial (ex.: 12 ou 0,5). Obrigatório quando as unidades diferem.", false},
+	ColunaCST:               {ColunaCST, "CST do ICMS (regime normal), com 2 dígitos.", true},
+	ColunaFCI:               {ColunaFCI, "Número da FCI. Obrigatório para CST Origem 3, 5 e 8.", true},
+	ColunaCBenef:            {ColunaCBenef, "Código de benefício fiscal da UF, exigido para alguns CSTs.", true},
+	ColunaServico:           {ColunaServico, "Item da lista de serviços da LC 116 (NN.NN). Obrigatório para Tipo Item 09.", true},
+	ColunaANP:               {ColunaANP, "Código de produto da ANP (9 dígitos). Obrigatório para combustíveis.", true},
+	ColunaCSTIBSCBS:         {ColunaCSTIBSCBS, "CST do IBS/CBS (3 dígitos), da reforma tributária.", true},
+	ColunaCClassTrib:        {ColunaCClassTrib, "Classificação tributária do IBS/CBS (6 dígitos).", true},
+}
+
+// camposModelo monta as colunas do modelo: o layout do ERP, as colunas das regras fiscais que não
+// fazem parte dele e as colunas configuradas no perfil de regras. O comentário de cada cabeçalho
+// indica a obrigatoriedade que a validação vai aplicar.
+func camposModelo(perfil *PerfilRegras) []campoModelo {
+	campos := append([]campoModelo(nil), camposLayout...)
+	presentes := make(map[string]bool, len(campos))
+	for _, campo := range campos {
+		presentes[campo.nome] = true
+	}
+
+	for _, nome := range colunasRegras {
+		if campo, ok := camposRegras[nome]; ok && !presentes[nome] {
+			campos = append(campos, campo)
+			presentes[nome] = true
+		}
+	}
+
+	if perfil != nil {
+		extras := make([]string, 0, len(perfil.Colunas))
+		for nome := range perfil.Colunas {
+			if !presentes[nome] {
+				extras = append(extras, nome)
+			}
+		}
+		sort.Strings(extras)
+		for _, nome := range extras {
+			campos = append(campos, campoModelo{nome, "Coluna configurada no perfil de regras.", false})
+		}
+	}
+
+	for i := range campos {
+		if texto := textoObrigatoriedade(perfil, campos[i].nome); texto != "" {
+			campos[i].descricao += " " + texto
+		}
+	}
+	return campos
+}
+
+// textoObrigatoriedade descreve a obrigatoriedade da coluna no perfil. As colunas com regra própria
+// já explicam a condição na descrição e ficam sem texto.
+func textoObrigatoriedade(perfil *PerfilRegras, nome string) string {
+	regra, ok := regraColunaPerfil(perfil, nome)
+	if !ok {
+		if colunasReforma[nome] {
+			return "Opcional enquanto o perfil de regras não habilita as regras da reforma."
+		}
+		return ""
+	}
+	switch regra.Obrigatoriedade {
+	case domain.ColunaObrigatoria:
+		return "Preenchimento obrigatório."
+	case domain.ColunaOpcional:
+		return "Preenchimento opcional."
+	case domain.ColunaCondicional:
+		if len(regra.Valores) == 0 {
+			return "Obrigatório quando '" + regra.Coluna + "' estiver preenchida."
+		}
+		return "Obrigatório quando '" + regra.Coluna + "' for " + strings.Join(regra.Valores, " ou ") + "."
+	}
+	return ""
+}
+
+// listasModelo liga cada coluna com lista suspensa à sua tabela de valores permitidos
+var listasModelo = []struct {
+	coluna string
+	tabela []CodigoTabela
+}{
+	{ColunaCSOSN, TabelaCSOSN},
+	{ColunaCSTOrigem, TabelaCSTOrigem},
+	{ColunaTipoItem, TabelaTipoItem},
+}
+
+// GerarModelo cria a planilha modelo para o cliente preencher: cabeçalhos esperados com comentários,
+// colunas de código formatadas como texto e listas suspensas alimentadas por uma aba oculta de tabelas.
+// O perfil de regras (opcional) acrescenta as colunas configuradas e define a obrigatoriedade descrita.
+func GerarModelo(sheetName string, perfil *PerfilRegras) (*excelize.File, error) {
+	f := excelize.NewFile()
+
+	if err := f.SetSheetName("Sheet1", sheetName); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	if err := escreverModelo(f, sheetName, camposModelo(perfil)); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("erro ao gerar planilha modelo: %w", err)
+	}
+
+	return f, nil
+}
+
+// escreverModelo (privada para uso interno) monta as duas abas do modelo
+func escreverModelo(f *excelize.File, sheetName string, campos []campoModelo) error {
+	estiloCabecalho, err := f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true, Color: "FFFFFF"},
+		Fill:      excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"1F4E78"}},
+		Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center", WrapText: true},
+	})
+	if err != nil {
+		return err
+	}
+	estiloTexto, err := f.NewStyle(&excelize.Style{NumFmt: 49}) // "@" — Texto
+	if err != nil {
+		return err
+	}
+
+	// Aba oculta com as tabelas de códigos: colunas de código e descrição lado a lado
+	if _, err := f.NewSheet(sheetTabelas); err != nil {
+		return err
+	}
+	origemListas := make(map[string]string)
+	for i, lista := range listasModelo {
+		colCodigo := indiceParaLetra(i * 3)
+		colDescricao := indiceParaLetra(i*3 + 1)
+
+		if err := f.SetCellStr(sheetTabelas, colCodigo+"1", lista.coluna); err != nil {
+			return err
+		}
+		if err := f.SetCellStr(sheetTabelas, colDescricao+"1", "Descrição"); err != nil {
+			return err
+		}
+		for j, item := range lista.tabela {
+			linha := j + 2
+			if err := f.SetCellStr(sheetTabelas, fmt.Sprintf("%s%d", colCodigo, linha), item.Codigo); err != nil {
+				return err
+			}
+			if err := f.SetCellStr(sheetTabelas, fmt.Sprintf("%s%d", colDescricao, linha), item.Descricao); err != nil {
+				return err
+			}
+		}
+		origemListas[lista.coluna] = fmt.Sprintf("%s!$%s$2:$%s$%d", sheetTabelas, colCodigo, colCodigo, len(lista.tabela)+1)
+	}
+	if err := f.SetSheetVisible(sheetTabelas, false); err != nil {
+		return err
+	}
+
+	// Aba de produtos: cabeçalhos, comentários, formatação e listas suspensas
+	for i, campo := range campos {
+		letra := indiceParaLetra(i)
+		celula := letra + "1"
+
+		if err := f.SetCellStr(sheetName, celula, campo.nome); err != nil {
+			return err
+		}
+		if err := f.AddComment(sheetName, excelize.Comment{
+			Cell:   celula,
+			Author: autorComentarios,
+			Text:   campo.descricao,
+		}); err != nil {
+			return err
+		}
+		if err := f.SetColWidth(sheetName, letra, letra, larguraColuna); err != nil {
+			return err
+		}
+
+		if campo.codigo {
+			if err := f.SetColStyle(sheetName, letra, estiloTexto); err != nil {
+				return err
+			}
+		}
+
+		if origem, ok := origemListas[campo.nome]; ok {
+			dv := excelize.NewDataValidation(true)
+			dv.Sqref = fmt.Sprintf("%s2:%s%d", letra, letra, linhasModelo+1)
+			dv.SetSqrefDropList(origem)
+			dv.SetInput(campo.nome, campo.descricao)
+			dv.SetError(excelize.DataValidationErrorStyleStop, campo.nome+" inválido",
+				"Escolha um dos valores da lista (tabela completa na aba "+sheetTabelas+").")
+			if err := f.AddDataValidation(sheetName, dv); err != nil {
+				return err
+			}
+		}
+	}
+
+	ultimaColuna := indiceParaLetra(len(campos) - 1)
+	if err := f.SetCellStyle(sheetName, "A1", ultimaColuna+"1", estiloCabecalho); err != nil {
+		return err
+	}
+	if err := f.SetPanes(sheetName, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	}); err != nil {
+		return err
+	}
+
+	indice, err := f.GetSheetIndex(sheetName)
+	if err != nil {
+		return err
+	}
+	f.SetActiveSheet(indice)
+	return nil
+}
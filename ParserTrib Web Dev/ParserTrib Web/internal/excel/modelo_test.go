@@ -0,0 +1,117 @@
+package excel
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestGerarModelo(t *testing.T) {
+	f, err := GerarModelo("Produto", nil)
+	if err != nil {
+		t.Fatalf("GerarModelo: %v", err)
+	}
+	defer f.Close()
+	campos := camposModelo(nil)
+
+	linhas, err := f.GetRows("Produto")
+	if err != nil || len(linhas) == 0 {
+		t.Fatalf("aba Produto sem cabeçalho: %v", err)
+	}
+	var esperados []string
+	for _, campo := range campos {
+		esperados = append(esperados, campo.nome)
+	}
+	if !slices.Equal(linhas[0], esperados) {
+		t.Errorf("cabeçalho = %q, esperado %q", linhas[0], esperados)
+	}
+
+	if visivel, _ := f.GetSheetVisible(sheetTabelas); visivel {
+		t.Errorf("aba %s deveria estar oculta", sheetTabelas)
+	}
+
+	validacoes, err := f.GetDataValidations("Produto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	listas := make(map[string]string)
+	for _, dv := range validacoes {
+		listas[dv.Sqref] = dv.Formula1
+	}
+	comLista := make(map[string]bool)
+	for _, lista := range listasModelo {
+		comLista[lista.coluna] = true
+	}
+	for i, campo := range campos {
+		letra := indiceParaLetra(i)
+		_, temLista := listas[letra+"2:"+letra+"5001"]
+		querLista := comLista[campo.nome]
+		if temLista != querLista {
+			t.Errorf("coluna %s (%s): lista suspensa = %v, esperado %v", letra, campo.nome, temLista, querLista)
+		}
+	}
+
+	for i, campo := range campos {
+		letra := indiceParaLetra(i)
+		estilo, err := f.GetColStyle("Produto", letra)
+		if err != nil {
+			t.Fatal(err)
+		}
+		formato := 0
+		if estilo != 0 {
+			s, err := f.GetStyle(estilo)
+			if err != nil {
+				t.Fatal(err)
+			}
+			formato = s.NumFmt
+		}
+		if (formato == 49) != campo.codigo {
+			t.Errorf("coluna %s (%s): formato %d, coluna de código = %v", letra, campo.nome, formato, campo.codigo)
+		}
+	}
+
+	comentarios, err := f.GetComments("Produto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comentarios) != len(campos) {
+		t.Errorf("%d comentários no cabeçalho, esperado %d", len(comentarios), len(campos))
+	}
+
+	if codigos, _ := f.GetRows(sheetTabelas); len(codigos) != max(len(TabelaCSOSN), len(TabelaCSTOrigem), len(TabelaTipoItem))+1 {
+		t.Errorf("aba %s com %d linhas", sheetTabelas, len(codigos))
+	}
+}
+
+func TestCamposModelo(t *testing.T) {
+	perfil := &PerfilRegras{Colunas: map[string]RegraColuna{"Referência": {Obrigatoriedade: "opcional"}}}
+	campos := camposModelo(perfil)
+
+	porNome := make(map[string]campoModelo, len(campos))
+	for _, campo := range campos {
+		if _, repetido := porNome[campo.nome]; repetido {
+			t.Errorf("coluna '%s' repetida no modelo", campo.nome)
+		}
+		porNome[campo.nome] = campo
+	}
+
+	for _, nome := range colunasRegras {
+		if _, ok := porNome[nome]; !ok {
+			t.Errorf("coluna de regra '%s' ausente do modelo (falta a descrição em camposRegras?)", nome)
+		}
+	}
+
+	casos := []struct {
+		coluna string
+		texto  string
+	}{
+		{ColunaDescricao, "Preenchimento obrigatório."},
+		{"Referência", "Preenchimento opcional."},
+		{ColunaCSTIBSCBS, "Opcional enquanto o perfil de regras não habilita as regras da reforma."},
+	}
+	for _, caso := range casos {
+		if campo := porNome[caso.coluna]; !strings.HasSuffix(campo.descricao, caso.texto) {
+			t.Errorf("descrição de '%s' = %q, esperado terminar com %q", caso.coluna, campo.descricao, caso.texto)
+		}
+	}
+}
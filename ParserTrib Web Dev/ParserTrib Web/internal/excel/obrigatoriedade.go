@@ -0,0 +1,98 @@
+package excel
+
+import (
+	"ParserTrib/internal/domain"
+	"math"
+	"strings"
+)
+
+// regraColuna devolve a obrigatoriedade da coluna: a do perfil, se configurada; senão, obrigatória.
+// As colunas com regra própria (colunasCondicionais) ficam de fora: o vazio delas é verificado pela
+// regra da coluna, e não por validarVazias. As colunas da reforma tributária também ficam de fora
+// enquanto o perfil não habilita as regras da reforma.
+func (v *Validator) regraColuna(nome string) (RegraColuna, bool) {
+	return regraColunaPerfil(v.perfil, nome)
+}
+
+// regraColunaPerfil aplica as regras de regraColuna ao perfil informado (nil = perfil padrão)
+func regraColunaPerfil(perfil *PerfilRegras, nome string) (RegraColuna, bool) {
+	if colunasCondicionais[nome] {
+		return RegraColuna{}, false
+	}
+	if perfil != nil {
+		if regra, ok := perfil.Colunas[nome]; ok {
+			return regra, true
+		}
+	}
+	if colunasReforma[nome] && !perfil.ReformaHabilitada() {
+		return RegraColuna{}, false
+	}
+	return RegraColuna{Obrigatoriedade: domain.ColunaObrigatoria}, true
+}
+
+// celulaExigida informa se a célula da coluna j na linha i (índice em rows) precisa estar preenchida.
+// Nas linhas de serviço, as colunas de mercadoria (NCM, CEST, GTIN) nunca são exigidas.
+func (v *Validator) celulaExigida(i, j int, regra RegraColuna, servico bool) bool {
+	if servico && colunasMercadoria[v.cabecalhos[j]] {
+		return false
+	}
+	switch regra.Obrigatoriedade {
+	case domain.ColunaObrigatoria:
+		return true
+	case domain.ColunaCondicional:
+		indice, existe := v.mapaIndices[regra.Coluna]
+		if !existe {
+			return false
+		}
+		valor := v.valorCelula(i, indice)
+		if valor == "" {
+			return false
+		}
+		if len(regra.Valores) == 0 {
+			return true
+		}
+		for _, esperado := range regra.Valores {
+			if strings.EqualFold(valor, strings.TrimSpace(esperado)) {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+// calcularCobertura mede, para cada coluna obrigatória ou condicional, quantas células exigidas
+// estão preenchidas. Colunas opcionais e as de regra própria não entram na cobertura.
+func (v *Validator) calcularCobertura() []domain.CoberturaColuna {
+	cobertura := make([]domain.CoberturaColuna, 0, len(v.cabecalhos))
+
+	for j, nome := range v.cabecalhos {
+		regra, ok := v.regraColuna(nome)
+		if !ok || regra.Obrigatoriedade == domain.ColunaOpcional {
+			continue
+		}
+
+		coluna := domain.CoberturaColuna{
+			Coluna:          indiceParaLetra(j),
+			NomeColuna:      nome,
+			Obrigatoriedade: regra.Obrigatoriedade,
+		}
+		for i := 1; i < len(v.rows); i++ {
+			if !v.celulaExigida(i, j, regra, v.linhaServico(i)) {
+				continue
+			}
+			coluna.Exigidas++
+			if v.valorCelula(i, j) != "" {
+				coluna.Preenchidas++
+			}
+		}
+		coluna.Percentual = 100
+		if coluna.Exigidas > 0 {
+			coluna.Percentual = math.Round(float64(coluna.Preenchidas)/float64(coluna.Exigidas)*1000) / 10
+		}
+		cobertura = append(cobertura, coluna)
+	}
+
+	return cobertura
+}
@@ -0,0 +1,30 @@
+package excel
+
+import (
+	"ParserTrib/internal/domain"
+	"slices"
+	"testing"
+)
+
+func TestCalcularCobertura(t *testing.T) {
+	perfil := &PerfilRegras{Colunas: map[string]RegraColuna{
+		"Referência": {Obrigatoriedade: domain.ColunaOpcional},
+		ColunaCEST:   {Obrigatoriedade: domain.ColunaCondicional, Coluna: ColunaTipoItem, Valores: []string{"00"}},
+	}}
+	cabecalhos := []string{ColunaCodigo, ColunaDescricao, ColunaFCI, "Referência", ColunaCEST, ColunaTipoItem}
+	v := novoValidatorTeste(cabecalhos,
+		[]string{"P1", "Caneta", "", "", "1234567", "00"},
+		[]string{"P2", "", "", "", "", "00"},
+		[]string{"P3", "Lápis", "", "", "", "04"},
+	).ComPerfilRegras(perfil)
+
+	esperada := []domain.CoberturaColuna{
+		{Coluna: "A", NomeColuna: ColunaCodigo, Obrigatoriedade: domain.ColunaObrigatoria, Exigidas: 3, Preenchidas: 3, Percentual: 100},
+		{Coluna: "B", NomeColuna: ColunaDescricao, Obrigatoriedade: domain.ColunaObrigatoria, Exigidas: 3, Preenchidas: 2, Percentual: 66.7},
+		{Coluna: "E", NomeColuna: ColunaCEST, Obrigatoriedade: domain.ColunaCondicional, Exigidas: 2, Preenchidas: 1, Percentual: 50},
+		{Coluna: "F", NomeColuna: ColunaTipoItem, Obrigatoriedade: domain.ColunaObrigatoria, Exigidas: 3, Preenchidas: 3, Percentual: 100},
+	}
+	if cobertura := v.calcularCobertura(); !slices.Equal(cobertura, esperada) {
+		t.Errorf("cobertura = %+v, esperado %+v", cobertura, esperada)
+	}
+}
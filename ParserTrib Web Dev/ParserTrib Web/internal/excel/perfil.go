@@ -0,0 +1,143 @@
+package excel
+
+import (
+	"ParserTrib/internal/domain"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// topValoresPerfil é a quantidade de valores mais frequentes listados por coluna
+const topValoresPerfil = 5
+
+// proporcaoTipoPerfil é a fração mínima dos valores preenchidos que precisa casar com um tipo
+const proporcaoTipoPerfil = 0.9
+
+var (
+	regexDigitos = regexp.MustCompile(`^\d+$`)
+	regexNumero  = regexp.MustCompile(`^-?(\d{1,3}(\.\d{3})*|\d+)(,\d+)?$|^-?\d+(\.\d+)?$`)
+	regexData    = regexp.MustCompile(`^(\d{1,2}/\d{1,2}/\d{2,4}|\d{4}-\d{2}-\d{2}|\d{1,2}-\d{1,2}-\d{2,4})( \d{1,2}:\d{2}(:\d{2})?)?$`)
+)
+
+// perfilar calcula o perfil de cada coluna do cabeçalho sobre as mesmas linhas já lidas pelo validador
+func (v *Validator) perfilar() []domain.PerfilColuna {
+	perfis := make([]domain.PerfilColuna, 0, len(v.cabecalhos))
+	totalLinhas := len(v.rows) - 1
+	if totalLinhas < 0 {
+		totalLinhas = 0
+	}
+
+	for j, nome := range v.cabecalhos {
+		frequencias := make(map[string]int)
+		perfil := domain.PerfilColuna{
+			Coluna:         indiceParaLetra(j),
+			NomeColuna:     nome,
+			MaisFrequentes: make([]domain.FrequenciaValor, 0),
+		}
+		var digitos, comZeroEsquerda, numeros, datas int
+
+		for i := 1; i < len(v.rows); i++ {
+			valor := ""
+			if j < len(v.rows[i]) {
+				valor = strings.TrimSpace(v.rows[i][j])
+			}
+			if valor == "" {
+				continue
+			}
+
+			perfil.Preenchidas++
+			frequencias[valor]++
+
+			tamanho := utf8.RuneCountInString(valor)
+			if perfil.Preenchidas == 1 || tamanho < perfil.TamanhoMinimo {
+				perfil.TamanhoMinimo = tamanho
+			}
+			if tamanho > perfil.TamanhoMaximo {
+				perfil.TamanhoMaximo = tamanho
+			}
+
+			switch {
+			case regexDigitos.MatchString(valor):
+				digitos++
+				numeros++
+				if len(valor) > 1 && valor[0] == '0' {
+					comZeroEsquerda++
+				}
+			case regexNumero.MatchString(valor):
+				numeros++
+			case regexData.MatchString(valor):
+				datas++
+			}
+		}
+
+		if totalLinhas > 0 {
+			perfil.TaxaPreenchimento = math.Round(float64(perfil.Preenchidas)/float64(totalLinhas)*1000) / 10
+		}
+		perfil.ValoresDistintos = len(frequencias)
+		perfil.MaisFrequentes = maisFrequentes(frequencias, topValoresPerfil)
+		perfil.TipoDetectado = detectarTipo(perfil, digitos, comZeroEsquerda, numeros, datas)
+		if perfil.TipoDetectado == domain.PerfilNumerico && ehColunaCodigo(nome) {
+			perfil.TipoDetectado = domain.PerfilCodigo
+		}
+
+		perfis = append(perfis, perfil)
+	}
+
+	return perfis
+}
+
+// detectarTipo classifica a coluna: só dígitos com zeros à esquerda ou com tamanho fixo longo
+// (GTIN, NCM...) é código; demais números são numéricos
+func detectarTipo(perfil domain.PerfilColuna, digitos, comZeroEsquerda, numeros, datas int) string {
+	if perfil.Preenchidas == 0 {
+		return domain.PerfilVazia
+	}
+
+	minimo := int(math.Ceil(float64(perfil.Preenchidas) * proporcaoTipoPerfil))
+	tamanhoFixoLongo := perfil.TamanhoMinimo == perfil.TamanhoMaximo && perfil.TamanhoMinimo >= 6
+	switch {
+	case datas >= minimo:
+		return domain.PerfilData
+	case digitos >= minimo && (comZeroEsquerda > 0 || tamanhoFixoLongo):
+		return domain.PerfilCodigo
+	case numeros >= minimo:
+		return domain.PerfilNumerico
+	default:
+		return domain.PerfilTexto
+	}
+}
+
+// ehColunaCodigo indica se a coluna guarda códigos fiscais, mesmo quando os valores parecem números
+func ehColunaCodigo(nome string) bool {
+	if nome == ColunaCSOSN {
+		return true
+	}
+	for _, coluna := range colunasCodigo {
+		if coluna.nome == nome {
+			return true
+		}
+	}
+	return false
+}
+
+// maisFrequentes devolve os n valores com mais ocorrências (empate: ordem alfabética)
+func maisFrequentes(frequencias map[string]int, n int) []domain.FrequenciaValor {
+	lista := make([]domain.FrequenciaValor, 0, len(frequencias))
+	for valor, ocorrencias := range frequencias {
+		lista = append(lista, domain.FrequenciaValor{Valor: valor, Ocorrencias: ocorrencias})
+	}
+
+	sort.Slice(lista, func(i, j int) bool {
+		if lista[i].Ocorrencias != lista[j].Ocorrencias {
+			return lista[i].Ocorrencias > lista[j].Ocorrencias
+		}
+		return lista[i].Valor < lista[j].Valor
+	})
+
+	if len(lista) > n {
+		lista = lista[:n]
+	}
+	return lista
+}
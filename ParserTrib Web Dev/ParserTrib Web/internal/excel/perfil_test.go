@@ -0,0 +1,101 @@
+package excel
+
+import (
+	"ParserTrib/internal/domain"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestPerfilar(t *testing.T) {
+	casos := []struct {
+		nome      string
+		valores   []string
+		coluna    string
+		perfil    domain.PerfilColuna
+		frequente []domain.FrequenciaValor
+	}{
+		{
+			nome:    "coluna vazia",
+			coluna:  "Observação",
+			valores: []string{"", " ", ""},
+			perfil:  domain.PerfilColuna{TipoDetectado: domain.PerfilVazia},
+		},
+		{
+			nome:      "texto com valores repetidos",
+			coluna:    "Descrição",
+			valores:   []string{"Caneta", "Lápis", "Caneta", "", "Borracha"},
+			perfil:    domain.PerfilColuna{Preenchidas: 4, TaxaPreenchimento: 80, ValoresDistintos: 3, TamanhoMinimo: 5, TamanhoMaximo: 8, TipoDetectado: domain.PerfilTexto},
+			frequente: []domain.FrequenciaValor{{Valor: "Caneta", Ocorrencias: 2}, {Valor: "Borracha", Ocorrencias: 1}, {Valor: "Lápis", Ocorrencias: 1}},
+		},
+		{
+			nome:    "dígitos com zero à esquerda são código",
+			coluna:  "Referência",
+			valores: []string{"0123", "4567", "089"},
+			perfil:  domain.PerfilColuna{Preenchidas: 3, TaxaPreenchimento: 100, ValoresDistintos: 3, TamanhoMinimo: 3, TamanhoMaximo: 4, TipoDetectado: domain.PerfilCodigo},
+		},
+		{
+			nome:    "dígitos com tamanho fixo longo são código",
+			coluna:  "Referência",
+			valores: []string{"123456", "654321"},
+			perfil:  domain.PerfilColuna{Preenchidas: 2, TaxaPreenchimento: 100, ValoresDistintos: 2, TamanhoMinimo: 6, TamanhoMaximo: 6, TipoDetectado: domain.PerfilCodigo},
+		},
+		{
+			nome:    "números no formato brasileiro",
+			coluna:  "Preço",
+			valores: []string{"10", "1.234,56", "7,5"},
+			perfil:  domain.PerfilColuna{Preenchidas: 3, TaxaPreenchimento: 100, ValoresDistintos: 3, TamanhoMinimo: 2, TamanhoMaximo: 8, TipoDetectado: domain.PerfilNumerico},
+		},
+		{
+			nome:    "coluna fiscal numérica é código",
+			coluna:  ColunaCSOSN,
+			valores: []string{"102", "500"},
+			perfil:  domain.PerfilColuna{Preenchidas: 2, TaxaPreenchimento: 100, ValoresDistintos: 2, TamanhoMinimo: 3, TamanhoMaximo: 3, TipoDetectado: domain.PerfilCodigo},
+		},
+		{
+			nome:    "datas",
+			coluna:  "Cadastro",
+			valores: []string{"01/02/2024", "2024-03-15", "15/03/24 10:30"},
+			perfil:  domain.PerfilColuna{Preenchidas: 3, TaxaPreenchimento: 100, ValoresDistintos: 3, TamanhoMinimo: 10, TamanhoMaximo: 14, TipoDetectado: domain.PerfilData},
+		},
+		{
+			nome:    "menos de 90% numérico é texto",
+			coluna:  "Peso",
+			valores: []string{"1", "2", "3", "4", "5", "6", "7", "8", "-", "não informado"},
+			perfil:  domain.PerfilColuna{Preenchidas: 10, TaxaPreenchimento: 100, ValoresDistintos: 10, TamanhoMinimo: 1, TamanhoMaximo: 13, TipoDetectado: domain.PerfilTexto},
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			var linhas [][]string
+			for _, valor := range caso.valores {
+				linhas = append(linhas, []string{valor})
+			}
+			perfis := novoValidatorTeste([]string{caso.coluna}, linhas...).perfilar()
+			if len(perfis) != 1 {
+				t.Fatalf("%d perfis, esperado 1", len(perfis))
+			}
+
+			obtido := perfis[0]
+			frequentes := obtido.MaisFrequentes
+			obtido.MaisFrequentes = nil
+			esperado := caso.perfil
+			esperado.Coluna, esperado.NomeColuna = "A", caso.coluna
+			if !reflect.DeepEqual(obtido, esperado) {
+				t.Errorf("perfil = %+v, esperado %+v", obtido, esperado)
+			}
+			if caso.frequente != nil && !slices.Equal(frequentes, caso.frequente) {
+				t.Errorf("mais frequentes = %v, esperado %v", frequentes, caso.frequente)
+			}
+		})
+	}
+}
+
+func TestMaisFrequentes(t *testing.T) {
+	frequencias := map[string]int{"UN": 4, "KG": 2, "CX": 2, "PC": 1, "LT": 1, "M": 1}
+	esperado := []domain.FrequenciaValor{{Valor: "UN", Ocorrencias: 4}, {Valor: "CX", Ocorrencias: 2}, {Valor: "KG", Ocorrencias: 2}, {Valor: "LT", Ocorrencias: 1}, {Valor: "M", Ocorrencias: 1}}
+	if obtido := maisFrequentes(frequencias, topValoresPerfil); !slices.Equal(obtido, esperado) {
+		t.Errorf("mais frequentes = %v, esperado %v", obtido, esperado)
+	}
+}
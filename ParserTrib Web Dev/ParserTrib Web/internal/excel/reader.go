@@ -81,6 +81,107 @@ func (r *Reader) obterTotalLinhas() (int, error) {
 	return len(rows) - 1, nil
 }
 
+// formatosNumericosPadrao traduz os IDs de formato embutidos do Excel mais comuns
+var formatosNumericosPadrao = map[int]string{
+	0:  "General",
+	1:  "0",
+	2:  "0.00",
+	3:  "#,##0",
+	4:  "#,##0.00",
+	9:  "0%",
+	10: "0.00%",
+	11: "0.00E+00",
+	12: "# ?/?",
+	14: "mm-dd-yy",
+	48: "##0.0E+0",
+	49: "@",
+}
+
+// ObterMetadadosCelulas retorna tipo, formato numérico e valor bruto de cada célula preenchida,
+// alinhado com as linhas de ObterTodasLinhas (mesmos índices de linha e coluna)
+func (r *Reader) ObterMetadadosCelulas(rows [][]string) ([][]domain.MetadadoCelula, error) {
+	formatos := make(map[int]string) // cache por estilo
+	metadados := make([][]domain.MetadadoCelula, len(rows))
+
+	for i, linha := range rows {
+		metadados[i] = make([]domain.MetadadoCelula, len(linha))
+		for j, valor := range linha {
+			if valor == "" {
+				continue
+			}
+
+			celula, err := excelize.CoordinatesToCellName(j+1, i+1)
+			if err != nil {
+				return nil, err
+			}
+
+			tipoCelula, err := r.arquivo.GetCellType(r.sheetName, celula)
+			if err != nil {
+				return nil, fmt.Errorf("erro ao ler tipo da célula %s: %w", celula, err)
+			}
+
+			bruto, err := r.arquivo.GetCellValue(r.sheetName, celula, excelize.Options{RawCellValue: true})
+			if err != nil {
+				return nil, fmt.Errorf("erro ao ler valor da célula %s: %w", celula, err)
+			}
+
+			idEstilo, err := r.arquivo.GetCellStyle(r.sheetName, celula)
+			if err != nil {
+				return nil, fmt.Errorf("erro ao ler estilo da célula %s: %w", celula, err)
+			}
+			formato, ok := formatos[idEstilo]
+			if !ok {
+				formato = r.formatoNumerico(idEstilo)
+				formatos[idEstilo] = formato
+			}
+
+			metadados[i][j] = domain.MetadadoCelula{
+				Tipo:          converterTipoCelula(tipoCelula),
+				FormatoNumero: formato,
+				ValorBruto:    bruto,
+			}
+		}
+	}
+
+	return metadados, nil
+}
+
+// formatoNumerico (privada para uso interno) devolve o código de formato do estilo
+func (r *Reader) formatoNumerico(idEstilo int) string {
+	estilo, err := r.arquivo.GetStyle(idEstilo)
+	if err != nil || estilo == nil {
+		return "General"
+	}
+	if estilo.CustomNumFmt != nil && *estilo.CustomNumFmt != "" {
+		return *estilo.CustomNumFmt
+	}
+	if formato, ok := formatosNumericosPadrao[estilo.NumFmt]; ok {
+		return formato
+	}
+	return fmt.Sprintf("id:%d", estilo.NumFmt)
+}
+
+// converterTipoCelula mapeia o tipo do excelize para domain.TipoCelula.
+// Células numéricas não trazem o atributo de tipo no XML, por isso "Unset" com valor é número.
+func converterTipoCelula(tipo excelize.CellType) domain.TipoCelula {
+	switch tipo {
+	case excelize.CellTypeUnset, excelize.CellTypeNumber:
+		return domain.CelulaNumero
+	case excelize.CellTypeSharedString, excelize.CellTypeInlineString:
+		return domain.CelulaTexto
+	case excelize.CellTypeBool:
+		return domain.CelulaBooleano
+	case excelize.CellTypeDate:
+		return domain.CelulaData
+	case excelize.CellTypeFormula:
+		return domain.CelulaFormula
+	case excelize.CellTypeError:
+		return domain.CelulaErro
+	default:
+		return domain.CelulaTexto
+	}
+}
+
 // ObterArquivo retorna arquvio excelize (para validador)
 func (r *Reader) ObterArquivo() *excelize.File {
 	return r.arquivo
@@ -0,0 +1,89 @@
+package excel
+
+import (
+	"ParserTrib/internal/domain"
+	"ParserTrib/internal/reforma"
+)
+
+// colunasReforma só são exigidas (VAZ001) quando o perfil habilita as regras da reforma tributária:
+// até lá, a planilha pode trazer as colunas vazias ou nem trazê-las
+var colunasReforma = map[string]bool{
+	ColunaCSTIBSCBS:  true,
+	ColunaCClassTrib: true,
+}
+
+// ComTabelaReforma define a tabela de CST IBS/CBS e cClassTrib usada pelas regras opcionais da
+// reforma tributária (aplicadas só quando habilitadas no perfil de regras)
+func (v *Validator) ComTabelaReforma(tabela *reforma.Tabela) *Validator {
+	v.tabelaReforma = tabela
+	return v
+}
+
+// ReformaHabilitada informa se o perfil liga alguma regra da reforma tributária
+func (p *PerfilRegras) ReformaHabilitada() bool {
+	return p.Habilitada(RegraCSTIBSCBS) || p.Habilitada(RegraCClassTrib) || p.Habilitada(RegraCClassTribCST)
+}
+
+// validarReforma confere as colunas CST IBS/CBS e cClassTrib contra a tabela e a compatibilidade
+// entre elas (a classificação pertence a um único CST). Sem tabela, nada é verificado.
+func (v *Validator) validarReforma() []domain.ErroValidacao {
+	var erros []domain.ErroValidacao
+
+	if v.tabelaReforma == nil || !v.perfil.ReformaHabilitada() {
+		return erros
+	}
+	indiceCST, temCST := v.mapaIndices[ColunaCSTIBSCBS]
+	indiceClass, temClass := v.mapaIndices[ColunaCClassTrib]
+	versao := " (tabela versão " + v.tabelaReforma.Versao + ")"
+
+	for i := 1; i < len(v.rows); i++ {
+		numLinha := i + 1
+
+		cst, cstValido := "", false
+		if temCST {
+			cst = v.valorCelula(i, indiceCST)
+			_, cstValido = v.tabelaReforma.CST(cst)
+			if cst != "" && !cstValido && v.perfil.Habilitada(RegraCSTIBSCBS) {
+				erros = append(erros, domain.ErroValidacao{
+					Linha:      numLinha,
+					Coluna:     indiceParaLetra(indiceCST),
+					NomeColuna: ColunaCSTIBSCBS,
+					Codigo:     RegraCSTIBSCBS,
+					Mensagem:   "CST IBS/CBS INVÁLIDO - não consta na tabela" + versao + " (atual: '" + cst + "')",
+				})
+			}
+		}
+
+		if !temClass {
+			continue
+		}
+		codigo := v.valorCelula(i, indiceClass)
+		if codigo == "" {
+			continue
+		}
+		classificacao, ok := v.tabelaReforma.ClassTrib(codigo)
+		if !ok {
+			if v.perfil.Habilitada(RegraCClassTrib) {
+				erros = append(erros, domain.ErroValidacao{
+					Linha:      numLinha,
+					Coluna:     indiceParaLetra(indiceClass),
+					NomeColuna: ColunaCClassTrib,
+					Codigo:     RegraCClassTrib,
+					Mensagem:   "cClassTrib INVÁLIDO - não consta na tabela" + versao + " (atual: '" + codigo + "')",
+				})
+			}
+			continue
+		}
+		if cstValido && classificacao.CST != cst && v.perfil.Habilitada(RegraCClassTribCST) {
+			erros = append(erros, domain.ErroValidacao{
+				Linha:      numLinha,
+				Coluna:     indiceParaLetra(indiceClass),
+				NomeColuna: ColunaCClassTrib,
+				Codigo:     RegraCClassTribCST,
+				Mensagem:   "cClassTrib INCOMPATÍVEL COM O CST IBS/CBS - " + codigo + " pertence ao CST " + classificacao.CST + " (CST atual: '" + cst + "')" + versao,
+			})
+		}
+	}
+
+	return erros
+}
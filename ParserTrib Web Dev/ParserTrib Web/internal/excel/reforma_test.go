@@ -0,0 +1,62 @@
+package excel
+
+import (
+	"ParserTrib/internal/reforma"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidarReforma(t *testing.T) {
+	caminho := filepath.Join(t.TempDir(), "reforma.json")
+	conteudo := `{"versao": "1.0", "vigencia": "2026-01-01",
+		"cst": [{"codigo": "000", "descricao": "Tributação integral"}, {"codigo": "200", "descricao": "Alíquota reduzida"}],
+		"cClassTrib": [{"codigo": "000001", "cst": "000", "descricao": "Tributadas integralmente"},
+		               {"codigo": "200001", "cst": "200", "descricao": "Redução de 60%"}]}`
+	if err := os.WriteFile(caminho, []byte(conteudo), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tabela, err := reforma.Carregar(caminho)
+	if err != nil {
+		t.Fatalf("Carregar: %v", err)
+	}
+
+	cabecalhos := []string{ColunaCodigo, ColunaCSTIBSCBS, ColunaCClassTrib}
+	linhas := [][]string{
+		{"P1", "000", "000001"},
+		{"P2", "999", "000001"},
+		{"P3", "000", "123456"},
+		{"P4", "000", "200001"},
+		{"P5", "", ""},
+	}
+	todas := &PerfilRegras{Habilitar: []string{RegraCSTIBSCBS, RegraCClassTrib, RegraCClassTribCST}}
+
+	casos := []struct {
+		nome      string
+		tabela    *reforma.Tabela
+		perfil    *PerfilRegras
+		esperados []string
+	}{
+		{
+			nome:      "todas as regras habilitadas",
+			tabela:    tabela,
+			perfil:    todas,
+			esperados: []string{"3 CST IBS/CBS IBS001", "4 cClassTrib IBS002", "5 cClassTrib IBS003"},
+		},
+		{
+			nome:      "só a regra de cClassTrib habilitada",
+			tabela:    tabela,
+			perfil:    &PerfilRegras{Habilitar: []string{RegraCClassTrib}},
+			esperados: []string{"4 cClassTrib IBS002"},
+		},
+		{nome: "regras não habilitadas no perfil", tabela: tabela},
+		{nome: "sem tabela", perfil: todas},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			v := novoValidatorTeste(cabecalhos, linhas...).ComTabelaReforma(caso.tabela).ComPerfilRegras(caso.perfil)
+			conferirErros(t, v.validarReforma(), caso.esperados)
+		})
+	}
+}
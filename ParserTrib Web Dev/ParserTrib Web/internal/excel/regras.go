@@ -0,0 +1,261 @@
+package excel
+
+import (
+	"ParserTrib/internal/domain"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Códigos estáveis das regras de validação — identificam o problema para integrações e
+// para o perfil de regras; nunca reaproveite um código para outra regra
+const (
+	RegraCelulaVazia             = "VAZ001"
+	RegraNCMFormato              = "NCM001"
+	RegraCSTOrigemNaoNumerico    = "CST001"
+	RegraCSTOrigemFaixa          = "CST002"
+	RegraCSOSNInvalido           = "CSOSN001"
+	RegraTipoItemNaoNumerico     = "TIPO001"
+	RegraTipoItemForaTabela      = "TIPO002"
+	RegraCodigoNumerico          = "NUM001"
+	RegraCodigoNotacaoCientifica = "NUM002"
+	RegraConsistencia            = "CONS001"
+	RegraFCIAusente              = "FCI001"
+	RegraFCIFormato              = "FCI002"
+	RegraFCIDesnecessaria        = "FCI003"
+	RegraCBenefAusente           = "CBENEF001"
+	RegraCBenefDesconhecido      = "CBENEF002"
+	RegraCBenefCST               = "CBENEF003"
+	RegraUnidadeForaTabela       = "UNID001"
+	RegraFatorConversao          = "UNID002"
+	RegraFatorUnidadesIguais     = "UNID003"
+	RegraUnidadeGTIN             = "UNID004"
+	RegraServicoAusente          = "SERV001"
+	RegraServicoFormato          = "SERV002"
+	RegraServicoForaTabela       = "SERV003"
+	RegraServicoNCM              = "SERV004"
+	RegraServicoDesnecessario    = "SERV005"
+	RegraANPAusente              = "ANP001"
+	RegraANPFormato              = "ANP002"
+	RegraANPForaTabela           = "ANP003"
+	RegraANPDescricao            = "ANP004"
+	RegraANPDesnecessario        = "ANP005"
+	RegraTextoTamanho            = "TXT001"
+	RegraTextoControle           = "TXT002"
+	RegraTextoEspacoEspecial     = "TXT003"
+	RegraTextoAcentuacao         = "TXT004"
+	RegraTextoEspacosPontas      = "TXT005"
+	RegraTextoProibido           = "TXT006"
+	RegraCSTIBSCBS               = "IBS001"
+	RegraCClassTrib              = "IBS002"
+	RegraCClassTribCST           = "IBS003"
+	RegraEFDAusenteNoEFD         = "EFD001"
+	RegraEFDAusenteNaPlanilha    = "EFD002"
+	RegraEFDDivergente           = "EFD003"
+)
+
+// Regra descreve uma regra de validação e a severidade usada quando o perfil não a altera
+type Regra struct {
+	Codigo     string
+	Descricao  string
+	Severidade string
+}
+
+// CatalogoRegras lista todas as regras de validação com sua severidade padrão
+var CatalogoRegras = []Regra{
+	{RegraCelulaVazia, "Célula vazia", domain.SeveridadeErro},
+	{RegraNCMFormato, "NCM sem exatamente 8 dígitos", domain.SeveridadeErro},
+	{RegraCSTOrigemNaoNumerico, "CST Origem não numérico", domain.SeveridadeErro},
+	{RegraCSTOrigemFaixa, "CST Origem fora da faixa 0 a 8", domain.SeveridadeErro},
+	{RegraCSOSNInvalido, "CSOSN fora da tabela", domain.SeveridadeErro},
+	{RegraTipoItemNaoNumerico, "Tipo Item não numérico", domain.SeveridadeErro},
+	{RegraTipoItemForaTabela, "Tipo Item fora da tabela", domain.SeveridadeErro},
+	{RegraCodigoNumerico, "Código armazenado como número", domain.SeveridadeErro},
+	{RegraCodigoNotacaoCientifica, "Código em notação científica", domain.SeveridadeErro},
+	{RegraConsistencia, "Código fiscal diferente da maioria dos produtos semelhantes", domain.SeveridadeAviso},
+	{RegraFCIAusente, "FCI ausente para CST Origem 3, 5 ou 8", domain.SeveridadeErro},
+	{RegraFCIFormato, "FCI fora do formato UUID de 36 caracteres", domain.SeveridadeErro},
+	{RegraFCIDesnecessaria, "FCI preenchida para origem que não a exige", domain.SeveridadeAviso},
+	{RegraCBenefAusente, "cBenef ausente para CST que exige benefício na UF", domain.SeveridadeErro},
+	{RegraCBenefDesconhecido, "cBenef fora da tabela da UF", domain.SeveridadeErro},
+	{RegraCBenefCST, "cBenef não permitido para o CST da linha", domain.SeveridadeErro},
+	{RegraUnidadeForaTabela, "Unidade fora da tabela de unidades", domain.SeveridadeErro},
+	{RegraFatorConversao, "Fator de conversão ausente ou inválido com unidades diferentes", domain.SeveridadeErro},
+	{RegraFatorUnidadesIguais, "Fator de conversão diferente de 1 com unidades iguais", domain.SeveridadeErro},
+	{RegraUnidadeGTIN, "Unidade tributável diferente entre linhas com o mesmo GTIN", domain.SeveridadeErro},
+	{RegraServicoAusente, "Código de serviço (LC 116) ausente para Tipo Item 09", domain.SeveridadeErro},
+	{RegraServicoFormato, "Código de serviço fora do formato NN.NN", domain.SeveridadeErro},
+	{RegraServicoForaTabela, "Código de serviço fora da lista da LC 116", domain.SeveridadeErro},
+	{RegraServicoNCM, "NCM de mercadoria em item de serviço", domain.SeveridadeErro},
+	{RegraServicoDesnecessario, "Código de serviço em item que não é serviço", domain.SeveridadeAviso},
+	{RegraANPAusente, "Código ANP ausente para NCM de combustível", domain.SeveridadeErro},
+	{RegraANPFormato, "Código ANP fora do formato de 9 dígitos", domain.SeveridadeErro},
+	{RegraANPForaTabela, "Código ANP fora da tabela de produtos da ANP", domain.SeveridadeErro},
+	{RegraANPDescricao, "Descrição sem termo em comum com a descrição ANP", domain.SeveridadeAviso},
+	{RegraANPDesnecessario, "Código ANP em item que não é combustível", domain.SeveridadeAviso},
+	{RegraTextoTamanho, "Texto acima do limite de caracteres da coluna", domain.SeveridadeErro},
+	{RegraTextoControle, "Caractere de controle ou invisível", domain.SeveridadeErro},
+	{RegraTextoEspacoEspecial, "Espaço não separável", domain.SeveridadeAviso},
+	{RegraTextoAcentuacao, "Acentuação corrompida (UTF-8 lido como Latin-1)", domain.SeveridadeErro},
+	{RegraTextoEspacosPontas, "Espaços no início ou no fim de célula de código", domain.SeveridadeErro},
+	{RegraTextoProibido, "Caractere não permitido na coluna", domain.SeveridadeErro},
+	{RegraCSTIBSCBS, "CST IBS/CBS fora da tabela", domain.SeveridadeErro},
+	{RegraCClassTrib, "cClassTrib fora da tabela", domain.SeveridadeErro},
+	{RegraCClassTribCST, "cClassTrib não pertence ao CST IBS/CBS da linha", domain.SeveridadeErro},
+	{RegraEFDAusenteNoEFD, "Produto da planilha sem registro 0200 no EFD", domain.SeveridadeAviso},
+	{RegraEFDAusenteNaPlanilha, "Registro 0200 do EFD sem produto na planilha", domain.SeveridadeAviso},
+	{RegraEFDDivergente, "TIPO_ITEM, COD_NCM ou CEST diferente do registro 0200 do EFD", domain.SeveridadeErro},
+}
+
+// regrasOpcionais só são aplicadas quando habilitadas no perfil (campo "habilitar")
+var regrasOpcionais = map[string]bool{
+	RegraCSTIBSCBS:     true,
+	RegraCClassTrib:    true,
+	RegraCClassTribCST: true,
+}
+
+// severidadesValidas são os valores aceitos no perfil de regras
+var severidadesValidas = map[string]bool{
+	domain.SeveridadeErro:  true,
+	domain.SeveridadeAviso: true,
+	domain.SeveridadeInfo:  true,
+}
+
+// obrigatoriedadesValidas são os valores aceitos para a obrigatoriedade de uma coluna no perfil
+var obrigatoriedadesValidas = map[string]bool{
+	domain.ColunaObrigatoria: true,
+	domain.ColunaOpcional:    true,
+	domain.ColunaCondicional: true,
+}
+
+// RegraColuna define se as células de uma coluna precisam estar preenchidas. Na obrigatoriedade
+// "condicional", a coluna é exigida quando Coluna está preenchida com um dos Valores (sem Valores,
+// basta Coluna estar preenchida).
+type RegraColuna struct {
+	Obrigatoriedade string   `json:"obrigatoriedade"`
+	Coluna          string   `json:"coluna,omitempty"`
+	Valores         []string `json:"valores,omitempty"`
+}
+
+// PerfilRegras ajusta o comportamento das regras para um cliente ou pipeline.
+// Exemplo de arquivo:
+//
+//	{"severidades": {"VAZ001": "aviso", "CONS001": "info"}, "habilitar": ["IBS001"],
+//	 "colunas": {"Observação": {"obrigatoriedade": "opcional"},
+//	             "CEST": {"obrigatoriedade": "condicional", "coluna": "Tipo Item", "valores": ["00"]}}}
+type PerfilRegras struct {
+	Severidades map[string]string      `json:"severidades"`
+	Habilitar   []string               `json:"habilitar"` // regras opcionais ligadas (ex.: reforma tributária)
+	Colunas     map[string]RegraColuna `json:"colunas"`   // obrigatoriedade por cabeçalho (padrão: obrigatória)
+}
+
+// CarregarPerfilRegras lê o perfil de regras em JSON. Arquivo inexistente não é erro:
+// devolve um perfil vazio, com as severidades padrão do catálogo.
+func CarregarPerfilRegras(caminho string) (*PerfilRegras, error) {
+	dados, err := os.ReadFile(caminho)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &PerfilRegras{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler perfil de regras: %w", err)
+	}
+
+	var perfil PerfilRegras
+	if err := json.Unmarshal(dados, &perfil); err != nil {
+		return nil, fmt.Errorf("erro ao interpretar perfil de regras '%s': %w", caminho, err)
+	}
+	if err := perfil.validar(); err != nil {
+		return nil, fmt.Errorf("perfil de regras '%s' inválido: %w", caminho, err)
+	}
+	return &perfil, nil
+}
+
+// validar confere se o perfil só referencia regras do catálogo, severidades conhecidas, em
+// "habilitar" regras opcionais e, em "colunas", obrigatoriedades conhecidas com condição coerente
+// (as colunas com regra própria, como FCI e Código ANP, só podem ser marcadas como opcionais)
+func (p *PerfilRegras) validar() error {
+	conhecidas := make(map[string]bool, len(CatalogoRegras))
+	for _, regra := range CatalogoRegras {
+		conhecidas[regra.Codigo] = true
+	}
+
+	var problemas []string
+	for codigo, severidade := range p.Severidades {
+		if !conhecidas[codigo] {
+			problemas = append(problemas, fmt.Sprintf("regra desconhecida '%s'", codigo))
+		} else if !severidadesValidas[severidade] {
+			problemas = append(problemas, fmt.Sprintf("severidade '%s' inválida para %s (use erro, aviso ou info)", severidade, codigo))
+		}
+	}
+	for _, codigo := range p.Habilitar {
+		if !conhecidas[codigo] {
+			problemas = append(problemas, fmt.Sprintf("regra desconhecida '%s'", codigo))
+		} else if !regrasOpcionais[codigo] {
+			problemas = append(problemas, fmt.Sprintf("%s não é opcional: está sempre habilitada", codigo))
+		}
+	}
+	for nome, coluna := range p.Colunas {
+		switch {
+		case !obrigatoriedadesValidas[coluna.Obrigatoriedade]:
+			problemas = append(problemas, fmt.Sprintf("obrigatoriedade '%s' inválida para a coluna '%s' (use obrigatoria, opcional ou condicional)", coluna.Obrigatoriedade, nome))
+		case colunasCondicionais[nome] && coluna.Obrigatoriedade != domain.ColunaOpcional:
+			// O vazio dessas colunas já é verificado pela regra própria (ex.: FCI001); VAZ001 duplicaria o erro
+			problemas = append(problemas, fmt.Sprintf("coluna '%s' tem regra própria de preenchimento e só aceita obrigatoriedade opcional no perfil", nome))
+		case coluna.Obrigatoriedade == domain.ColunaCondicional && (coluna.Coluna == "" || coluna.Coluna == nome):
+			problemas = append(problemas, fmt.Sprintf("coluna '%s' condicional precisa indicar outra coluna em \"coluna\"", nome))
+		case coluna.Obrigatoriedade != domain.ColunaCondicional && (coluna.Coluna != "" || len(coluna.Valores) > 0):
+			problemas = append(problemas, fmt.Sprintf("coluna '%s': \"coluna\" e \"valores\" só se aplicam à obrigatoriedade condicional", nome))
+		}
+	}
+	if len(problemas) > 0 {
+		sort.Strings(problemas)
+		return errors.New(strings.Join(problemas, "; "))
+	}
+	return nil
+}
+
+// Severidade retorna a severidade da regra: a do perfil, se configurada, ou a padrão do catálogo
+func (p *PerfilRegras) Severidade(codigo string) string {
+	if p != nil {
+		if severidade, ok := p.Severidades[codigo]; ok {
+			return severidade
+		}
+	}
+	for _, regra := range CatalogoRegras {
+		if regra.Codigo == codigo {
+			return regra.Severidade
+		}
+	}
+	return domain.SeveridadeErro
+}
+
+// Habilitada informa se a regra é aplicada: as obrigatórias sempre, as opcionais só se listadas no perfil
+func (p *PerfilRegras) Habilitada(codigo string) bool {
+	if !regrasOpcionais[codigo] {
+		return true
+	}
+	return p != nil && slices.Contains(p.Habilitar, codigo)
+}
+
+// Personalizado indica se o perfil altera alguma severidade padrão, habilita regras opcionais ou
+// define a obrigatoriedade de alguma coluna
+func (p *PerfilRegras) Personalizado() bool {
+	return p != nil && (len(p.Severidades) > 0 || len(p.Habilitar) > 0 || len(p.Colunas) > 0)
+}
+
+// aplicarSeveridades preenche a severidade de cada problema encontrado conforme o perfil
+func (p *PerfilRegras) aplicarSeveridades(resultado *domain.ResultadoValidacaoCompleto) {
+	for _, categoria := range resultado.Categorias() {
+		for i := range categoria.Erros {
+			categoria.Erros[i].Severidade = p.Severidade(categoria.Erros[i].Codigo)
+		}
+	}
+	for i := range resultado.AvisosConsistencia {
+		resultado.AvisosConsistencia[i].Severidade = p.Severidade(resultado.AvisosConsistencia[i].Codigo)
+	}
+}
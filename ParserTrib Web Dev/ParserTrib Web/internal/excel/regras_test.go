@@ -0,0 +1,169 @@
+package excel
+
+import (
+	"ParserTrib/internal/domain"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPerfilRegrasValidar(t *testing.T) {
+	casos := []struct {
+		nome   string
+		perfil PerfilRegras
+		erro   string
+	}{
+		{
+			nome: "perfil válido",
+			perfil: PerfilRegras{
+				Severidades: map[string]string{RegraCelulaVazia: domain.SeveridadeAviso, RegraConsistencia: domain.SeveridadeInfo},
+				Habilitar:   []string{RegraCSTIBSCBS},
+				Colunas: map[string]RegraColuna{
+					"Referência": {Obrigatoriedade: domain.ColunaOpcional},
+					ColunaCEST:   {Obrigatoriedade: domain.ColunaCondicional, Coluna: ColunaTipoItem, Valores: []string{"00"}},
+					ColunaFCI:    {Obrigatoriedade: domain.ColunaOpcional},
+				},
+			},
+		},
+		{
+			nome:   "regra desconhecida",
+			perfil: PerfilRegras{Severidades: map[string]string{"XYZ001": domain.SeveridadeAviso}},
+			erro:   "regra desconhecida 'XYZ001'",
+		},
+		{
+			nome:   "severidade inválida",
+			perfil: PerfilRegras{Severidades: map[string]string{RegraCelulaVazia: "grave"}},
+			erro:   "severidade 'grave' inválida",
+		},
+		{
+			nome:   "habilitar regra que não é opcional",
+			perfil: PerfilRegras{Habilitar: []string{RegraNCMFormato}},
+			erro:   "NCM001 não é opcional",
+		},
+		{
+			nome:   "obrigatoriedade inválida",
+			perfil: PerfilRegras{Colunas: map[string]RegraColuna{"Referência": {Obrigatoriedade: "sempre"}}},
+			erro:   "obrigatoriedade 'sempre' inválida",
+		},
+		{
+			nome:   "coluna com regra própria só aceita opcional",
+			perfil: PerfilRegras{Colunas: map[string]RegraColuna{ColunaFCI: {Obrigatoriedade: domain.ColunaObrigatoria}}},
+			erro:   "coluna 'FCI' tem regra própria de preenchimento",
+		},
+		{
+			nome:   "condicional sem outra coluna",
+			perfil: PerfilRegras{Colunas: map[string]RegraColuna{ColunaCEST: {Obrigatoriedade: domain.ColunaCondicional, Coluna: ColunaCEST}}},
+			erro:   "condicional precisa indicar outra coluna",
+		},
+		{
+			nome:   "condição em coluna que não é condicional",
+			perfil: PerfilRegras{Colunas: map[string]RegraColuna{ColunaCEST: {Obrigatoriedade: domain.ColunaOpcional, Valores: []string{"00"}}}},
+			erro:   "só se aplicam à obrigatoriedade condicional",
+		},
+		{
+			nome:   "vários problemas em ordem alfabética",
+			perfil: PerfilRegras{Severidades: map[string]string{"ZZZ001": domain.SeveridadeAviso, "AAA001": domain.SeveridadeAviso}},
+			erro:   "regra desconhecida 'AAA001'; regra desconhecida 'ZZZ001'",
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			err := caso.perfil.validar()
+			if caso.erro == "" {
+				if err != nil {
+					t.Errorf("erro = %v, esperado nenhum", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), caso.erro) {
+				t.Errorf("erro = %v, esperado conter %q", err, caso.erro)
+			}
+		})
+	}
+}
+
+func TestCarregarPerfilRegras(t *testing.T) {
+	diretorio := t.TempDir()
+	gravar := func(nome, conteudo string) string {
+		caminho := filepath.Join(diretorio, nome)
+		if err := os.WriteFile(caminho, []byte(conteudo), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return caminho
+	}
+
+	t.Run("arquivo inexistente usa as severidades padrão", func(t *testing.T) {
+		perfil, err := CarregarPerfilRegras(filepath.Join(diretorio, "nao_existe.json"))
+		if err != nil {
+			t.Fatalf("CarregarPerfilRegras: %v", err)
+		}
+		if perfil.Personalizado() || perfil.Severidade(RegraCelulaVazia) != domain.SeveridadeErro {
+			t.Errorf("perfil = %+v, esperado perfil vazio", perfil)
+		}
+	})
+
+	t.Run("perfil válido", func(t *testing.T) {
+		perfil, err := CarregarPerfilRegras(gravar("valido.json", `{"severidades": {"VAZ001": "aviso"}}`))
+		if err != nil {
+			t.Fatalf("CarregarPerfilRegras: %v", err)
+		}
+		if !perfil.Personalizado() || perfil.Severidade(RegraCelulaVazia) != domain.SeveridadeAviso {
+			t.Errorf("perfil = %+v, esperado VAZ001 como aviso", perfil)
+		}
+	})
+
+	for nome, conteudo := range map[string]string{
+		"json_invalido.json":  `{"severidades": `,
+		"regra_invalida.json": `{"severidades": {"VAZ001": "grave"}}`,
+	} {
+		t.Run(nome, func(t *testing.T) {
+			if _, err := CarregarPerfilRegras(gravar(nome, conteudo)); err == nil {
+				t.Error("esperado erro ao carregar o perfil")
+			}
+		})
+	}
+}
+
+func TestSeveridade(t *testing.T) {
+	var semPerfil *PerfilRegras
+	perfil := &PerfilRegras{Severidades: map[string]string{RegraNCMFormato: domain.SeveridadeInfo}}
+
+	casos := []struct {
+		perfil   *PerfilRegras
+		codigo   string
+		esperado string
+	}{
+		{semPerfil, RegraNCMFormato, domain.SeveridadeErro},
+		{semPerfil, RegraConsistencia, domain.SeveridadeAviso},
+		{perfil, RegraNCMFormato, domain.SeveridadeInfo},
+		{perfil, RegraCSOSNInvalido, domain.SeveridadeErro},
+		{perfil, "XYZ001", domain.SeveridadeErro},
+	}
+
+	for _, caso := range casos {
+		if obtida := caso.perfil.Severidade(caso.codigo); obtida != caso.esperado {
+			t.Errorf("Severidade(%q) = %q, esperado %q", caso.codigo, obtida, caso.esperado)
+		}
+	}
+}
+
+func TestValidarTudoAplicaSeveridades(t *testing.T) {
+	cabecalhos := []string{ColunaDescricao, ColunaNCM, ColunaCSOSN}
+	perfil := &PerfilRegras{Severidades: map[string]string{RegraCelulaVazia: domain.SeveridadeAviso}}
+	resultado := novoValidatorTeste(cabecalhos,
+		[]string{"CANETA AZUL", "96081000", ""},
+		[]string{"CANETA AZUL", "123", "102"},
+	).ComPerfilRegras(perfil).ValidarTudo(2)
+
+	severidades := make(map[string]string)
+	for _, categoria := range resultado.Categorias() {
+		for _, e := range categoria.Erros {
+			severidades[e.Codigo] = e.Severidade
+		}
+	}
+	if severidades[RegraCelulaVazia] != domain.SeveridadeAviso || severidades[RegraNCMFormato] != domain.SeveridadeErro {
+		t.Errorf("severidades = %v, esperado VAZ001 aviso e NCM001 erro", severidades)
+	}
+}
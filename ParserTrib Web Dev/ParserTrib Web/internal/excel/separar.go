@@ -0,0 +1,136 @@
+package excel
+
+import (
+	"ParserTrib/internal/domain"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// cabecalhoErros é o título da coluna acrescentada à planilha de linhas rejeitadas
+const cabecalhoErros = "Erros de Validação"
+
+// ResultadoSeparacao informa quantas linhas foram para cada planilha
+type ResultadoSeparacao struct {
+	Validas    int
+	Rejeitadas int
+}
+
+// SepararLinhas grava duas cópias da planilha de origem, preservando cabeçalhos, estilos e demais abas:
+// destinoValidas só com as linhas sem problemas de severidade erro e destinoRejeitadas só com as linhas
+// com erro, acrescidas de uma coluna com as mensagens. Avisos, informativos e ignorados não rejeitam a linha.
+func SepararLinhas(origem, sheetName string, resultado domain.ResultadoValidacaoCompleto, destinoValidas, destinoRejeitadas string) (ResultadoSeparacao, error) {
+	var separacao ResultadoSeparacao
+
+	mensagens := mensagensPorLinha(resultado)
+
+	f, err := excelize.OpenFile(origem)
+	if err != nil {
+		return separacao, fmt.Errorf("erro ao abrir arquivo: %w", err)
+	}
+	rows, err := f.GetRows(sheetName)
+	f.Close()
+	if err != nil {
+		return separacao, fmt.Errorf("erro ao ler linhas: %w", err)
+	}
+	if len(rows) == 0 {
+		return separacao, fmt.Errorf("a aba '%s' está vazia", sheetName)
+	}
+
+	var linhasValidas, linhasRejeitadas []int
+	for linha := 2; linha <= len(rows); linha++ {
+		if _, rejeitada := mensagens[linha]; rejeitada {
+			linhasRejeitadas = append(linhasRejeitadas, linha)
+		} else {
+			linhasValidas = append(linhasValidas, linha)
+		}
+	}
+	separacao.Validas = len(linhasValidas)
+	separacao.Rejeitadas = len(linhasRejeitadas)
+
+	// Planilha de válidas: remove as rejeitadas
+	if err := gravarSemLinhas(origem, sheetName, destinoValidas, linhasRejeitadas, nil); err != nil {
+		return separacao, fmt.Errorf("erro ao gravar planilha de linhas válidas: %w", err)
+	}
+
+	// Planilha de rejeitadas: escreve as mensagens antes de remover as válidas (as linhas ainda
+	// estão na posição original)
+	colunaErros := indiceParaLetra(len(rows[0]))
+	anotar := func(f *excelize.File) error {
+		if err := f.SetCellStr(sheetName, colunaErros+"1", cabecalhoErros); err != nil {
+			return err
+		}
+		estilo, err := f.GetCellStyle(sheetName, indiceParaLetra(len(rows[0])-1)+"1")
+		if err != nil {
+			return err
+		}
+		if err := f.SetCellStyle(sheetName, colunaErros+"1", colunaErros+"1", estilo); err != nil {
+			return err
+		}
+		for _, linha := range linhasRejeitadas {
+			celula := fmt.Sprintf("%s%d", colunaErros, linha)
+			if err := f.SetCellStr(sheetName, celula, strings.Join(mensagens[linha], " | ")); err != nil {
+				return err
+			}
+		}
+		return f.SetColWidth(sheetName, colunaErros, colunaErros, 80)
+	}
+	if err := gravarSemLinhas(origem, sheetName, destinoRejeitadas, linhasValidas, anotar); err != nil {
+		return separacao, fmt.Errorf("erro ao gravar planilha de linhas rejeitadas: %w", err)
+	}
+
+	return separacao, nil
+}
+
+// gravarSemLinhas copia a origem para destino sem as linhas informadas, aplicando antes o ajuste opcional
+func gravarSemLinhas(origem, sheetName, destino string, remover []int, ajustar func(*excelize.File) error) error {
+	f, err := excelize.OpenFile(origem)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if ajustar != nil {
+		if err := ajustar(f); err != nil {
+			return err
+		}
+	}
+
+	// De baixo para cima, para que a remoção não desloque as linhas ainda pendentes
+	ordenadas := append([]int(nil), remover...)
+	sort.Sort(sort.Reverse(sort.IntSlice(ordenadas)))
+	for _, linha := range ordenadas {
+		if err := f.RemoveRow(sheetName, linha); err != nil {
+			return err
+		}
+	}
+
+	return f.SaveAs(destino)
+}
+
+// mensagensPorLinha agrupa por linha as mensagens dos problemas com severidade erro
+func mensagensPorLinha(resultado domain.ResultadoValidacaoCompleto) map[int][]string {
+	mensagens := make(map[int][]string)
+
+	problemas := append([]domain.ErroValidacao(nil), resultado.AvisosConsistencia...)
+	for _, categoria := range resultado.Categorias() {
+		problemas = append(problemas, categoria.Erros...)
+	}
+	// Na ordem das colunas da planilha (B antes de AA)
+	sort.SliceStable(problemas, func(i, j int) bool {
+		if len(problemas[i].Coluna) != len(problemas[j].Coluna) {
+			return len(problemas[i].Coluna) < len(problemas[j].Coluna)
+		}
+		return problemas[i].Coluna < problemas[j].Coluna
+	})
+
+	for _, p := range problemas {
+		if p.Severidade != "" && p.Severidade != domain.SeveridadeErro {
+			continue
+		}
+		mensagens[p.Linha] = append(mensagens[p.Linha], fmt.Sprintf("[%s] %s: %s", p.Codigo, p.NomeColuna, p.Mensagem))
+	}
+	return mensagens
+}
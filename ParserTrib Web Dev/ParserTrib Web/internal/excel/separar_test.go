@@ -0,0 +1,90 @@
+package excel
+
+import (
+	"ParserTrib/internal/domain"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestSepararLinhas(t *testing.T) {
+	diretorio := t.TempDir()
+	origem := filepath.Join(diretorio, "produtos.xlsx")
+
+	f := excelize.NewFile()
+	f.SetSheetName("Sheet1", "Produto")
+	f.SetSheetRow("Produto", "A1", &[]any{ColunaCodigo, ColunaNCM, ColunaCSOSN})
+	f.SetSheetRow("Produto", "A2", &[]any{"P1", "96081000", "102"})
+	f.SetSheetRow("Produto", "A3", &[]any{"P2", "123", "999"})
+	f.SetSheetRow("Produto", "A4", &[]any{"P3", "96091000", "102"})
+	f.SetSheetRow("Produto", "A5", &[]any{"P4", "", "102"})
+	negrito, _ := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	f.SetCellStyle("Produto", "A1", "C1", negrito)
+	f.NewSheet("Tabelas")
+	f.SetCellStr("Tabelas", "A1", "CSOSN")
+	if err := f.SaveAs(origem); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	resultado := domain.ResultadoValidacaoCompleto{
+		ErrosNCM: []domain.ErroValidacao{
+			{Linha: 3, Coluna: "B", NomeColuna: ColunaNCM, Codigo: RegraNCMFormato, Severidade: domain.SeveridadeErro, Mensagem: "NCM INVÁLIDO"},
+		},
+		ErrosCSOSN: []domain.ErroValidacao{
+			{Linha: 3, Coluna: "C", NomeColuna: ColunaCSOSN, Codigo: RegraCSOSNInvalido, Severidade: domain.SeveridadeErro, Mensagem: "CSOSN INVÁLIDO"},
+		},
+		ErrosVazias: []domain.ErroValidacao{
+			{Linha: 5, Coluna: "B", NomeColuna: ColunaNCM, Codigo: RegraCelulaVazia, Severidade: domain.SeveridadeAviso, Mensagem: "CÉLULA VAZIA"},
+		},
+		AvisosConsistencia: []domain.ErroValidacao{
+			{Linha: 4, Coluna: "B", NomeColuna: ColunaNCM, Codigo: RegraConsistencia, Severidade: domain.SeveridadeAviso, Mensagem: "difere da maioria"},
+		},
+	}
+
+	destinoValidas := filepath.Join(diretorio, "validas.xlsx")
+	destinoRejeitadas := filepath.Join(diretorio, "rejeitadas.xlsx")
+	separacao, err := SepararLinhas(origem, "Produto", resultado, destinoValidas, destinoRejeitadas)
+	if err != nil {
+		t.Fatalf("SepararLinhas: %v", err)
+	}
+	if separacao != (ResultadoSeparacao{Validas: 3, Rejeitadas: 1}) {
+		t.Errorf("separação = %+v, esperado 3 válidas e 1 rejeitada", separacao)
+	}
+
+	conferir := func(caminho string, esperado [][]string) {
+		t.Helper()
+		f, err := excelize.OpenFile(caminho)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+
+		rows, err := f.GetRows("Produto")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(rows, esperado) {
+			t.Errorf("%s: linhas = %q, esperado %q", filepath.Base(caminho), rows, esperado)
+		}
+		if estilo, _ := f.GetCellStyle("Produto", "A1"); estilo != negrito {
+			t.Errorf("%s: estilo do cabeçalho = %d, esperado %d", filepath.Base(caminho), estilo, negrito)
+		}
+		if valor, _ := f.GetCellValue("Tabelas", "A1"); valor != "CSOSN" {
+			t.Errorf("%s: aba Tabelas não foi preservada", filepath.Base(caminho))
+		}
+	}
+
+	conferir(destinoValidas, [][]string{
+		{ColunaCodigo, ColunaNCM, ColunaCSOSN},
+		{"P1", "96081000", "102"},
+		{"P3", "96091000", "102"},
+		{"P4", "", "102"},
+	})
+	conferir(destinoRejeitadas, [][]string{
+		{ColunaCodigo, ColunaNCM, ColunaCSOSN, cabecalhoErros},
+		{"P2", "123", "999", "[NCM001] NCM: NCM INVÁLIDO | [CSOSN001] CSOSN: CSOSN INVÁLIDO"},
+	})
+}
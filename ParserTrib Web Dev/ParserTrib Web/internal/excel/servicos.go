@@ -0,0 +1,111 @@
+package excel
+
+import (
+	"ParserTrib/internal/domain"
+	"regexp"
+)
+
+// TipoItemServico é o Tipo Item das linhas de serviço: nelas valem as regras da lista da LC 116
+// no lugar das regras de mercadoria (NCM, CEST e GTIN)
+const TipoItemServico = "09"
+
+// regexServico valida o item da lista de serviços da LC 116 no formato da NF-e (cListServ): "01.07"
+var regexServico = regexp.MustCompile(`^\d{2}\.\d{2}$`)
+
+// ncmServico são os valores aceitos no NCM de um serviço, além do vazio
+var ncmServico = map[string]bool{"00": true, "00000000": true}
+
+// colunasMercadoria só se aplicam a mercadorias: nas linhas de serviço não são exigidas nem validadas
+var colunasMercadoria = map[string]bool{ColunaNCM: true, ColunaCEST: true, ColunaGTIN: true}
+
+// CarregarTabelaServicos lê a lista de serviços da LC 116 de um CSV com as colunas código e
+// descrição (ex.: "01.07;Suporte técnico em informática..."). Sem a tabela, só o formato é validado.
+func CarregarTabelaServicos(caminho string) ([]CodigoTabela, error) {
+	return lerTabelaCSV(caminho, "serviços", "ITEM")
+}
+
+// ComServicos define a lista de serviços da LC 116 usada na validação dos itens de serviço
+func (v *Validator) ComServicos(tabela []CodigoTabela) *Validator {
+	if len(tabela) > 0 {
+		v.servicos = mapaCodigos(tabela)
+	}
+	return v
+}
+
+// linhaServico informa se a linha (índice em rows) é de serviço, pelo Tipo Item
+func (v *Validator) linhaServico(i int) bool {
+	indice, existe := v.mapaIndices[ColunaTipoItem]
+	return existe && v.valorCelula(i, indice) == TipoItemServico
+}
+
+// validarServicos aplica as regras dos itens de serviço (Tipo Item 09): código da lista da LC 116
+// obrigatório, no formato NN.NN e presente na tabela (quando carregada), e NCM vazio ou "00000000".
+// Código de serviço em linha de mercadoria gera aviso. Sem a coluna do código, as linhas de serviço
+// são apontadas na célula de Tipo Item.
+func (v *Validator) validarServicos() []domain.ErroValidacao {
+	var erros []domain.ErroValidacao
+
+	indiceTipo, existe := v.mapaIndices[ColunaTipoItem]
+	if !existe {
+		return erros
+	}
+	indiceServico, temServico := v.mapaIndices[ColunaServico]
+	indiceNCM, temNCM := v.mapaIndices[ColunaNCM]
+
+	for i := 1; i < len(v.rows); i++ {
+		numLinha := i + 1
+		tipo := v.valorCelula(i, indiceTipo)
+		servico := tipo == TipoItemServico
+
+		if servico && temNCM {
+			if ncm := v.valorCelula(i, indiceNCM); ncm != "" && !ncmServico[ncm] {
+				erros = append(erros, domain.ErroValidacao{
+					Linha:      numLinha,
+					Coluna:     indiceParaLetra(indiceNCM),
+					NomeColuna: ColunaNCM,
+					Codigo:     RegraServicoNCM,
+					Mensagem:   "NCM DE MERCADORIA EM ITEM DE SERVIÇO - para Tipo Item 09 deixe o NCM vazio ou use 00000000 (atual: '" + ncm + "')",
+				})
+			}
+		}
+
+		if !temServico {
+			if servico {
+				erros = append(erros, domain.ErroValidacao{
+					Linha:      numLinha,
+					Coluna:     indiceParaLetra(indiceTipo),
+					NomeColuna: ColunaTipoItem,
+					Codigo:     RegraServicoAusente,
+					Mensagem:   "CÓDIGO DE SERVIÇO OBRIGATÓRIO - Tipo Item 09 exige o item da lista da LC 116, mas a planilha não tem a coluna '" + ColunaServico + "'",
+				})
+			}
+			continue
+		}
+
+		codigo := v.valorCelula(i, indiceServico)
+		erro := domain.ErroValidacao{
+			Linha:      numLinha,
+			Coluna:     indiceParaLetra(indiceServico),
+			NomeColuna: ColunaServico,
+		}
+		switch {
+		case servico && codigo == "":
+			erro.Codigo = RegraServicoAusente
+			erro.Mensagem = "CÓDIGO DE SERVIÇO OBRIGATÓRIO - Tipo Item 09 exige o item da lista da LC 116 (ex.: 01.07)"
+		case codigo != "" && !servico && tipo != "":
+			erro.Codigo = RegraServicoDesnecessario
+			erro.Mensagem = "CÓDIGO DE SERVIÇO EM ITEM QUE NÃO É SERVIÇO - só o Tipo Item 09 usa a lista da LC 116 (Tipo Item atual: " + tipo + ")"
+		case codigo != "" && !regexServico.MatchString(codigo):
+			erro.Codigo = RegraServicoFormato
+			erro.Mensagem = "CÓDIGO DE SERVIÇO INVÁLIDO - deve estar no formato NN.NN da lista da LC 116, ex.: 01.07 (atual: '" + codigo + "')"
+		case codigo != "" && v.servicos != nil && !v.servicos[codigo]:
+			erro.Codigo = RegraServicoForaTabela
+			erro.Mensagem = "CÓDIGO DE SERVIÇO FORA DA TABELA - não consta na lista de serviços da LC 116 (atual: '" + codigo + "')"
+		default:
+			continue
+		}
+		erros = append(erros, erro)
+	}
+
+	return erros
+}
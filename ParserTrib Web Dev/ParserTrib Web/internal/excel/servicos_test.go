@@ -0,0 +1,61 @@
+package excel
+
+import "testing"
+
+func TestValidarServicos(t *testing.T) {
+	tabela := []CodigoTabela{{Codigo: "01.07", Descricao: "Suporte técnico em informática"}}
+
+	casos := []struct {
+		nome       string
+		tabela     []CodigoTabela
+		cabecalhos []string
+		linhas     [][]string
+		esperados  []string
+	}{
+		{
+			nome:       "serviço com código da lista e NCM aceito",
+			tabela:     tabela,
+			cabecalhos: []string{ColunaCodigo, ColunaTipoItem, ColunaNCM, ColunaServico},
+			linhas:     [][]string{{"S1", "09", "", "01.07"}, {"S2", "09", "00000000", "01.07"}},
+		},
+		{
+			nome:       "código obrigatório, formato e tabela",
+			tabela:     tabela,
+			cabecalhos: []string{ColunaCodigo, ColunaTipoItem, ColunaNCM, ColunaServico},
+			linhas:     [][]string{{"S1", "09", "", ""}, {"S2", "09", "", "1.07"}, {"S3", "09", "", "99.99"}},
+			esperados:  []string{"2 Código Serviço SERV001", "3 Código Serviço SERV002", "4 Código Serviço SERV003"},
+		},
+		{
+			nome:       "sem tabela só o formato é verificado",
+			cabecalhos: []string{ColunaCodigo, ColunaTipoItem, ColunaServico},
+			linhas:     [][]string{{"S1", "09", "99.99"}},
+		},
+		{
+			nome:       "NCM de mercadoria em serviço",
+			tabela:     tabela,
+			cabecalhos: []string{ColunaCodigo, ColunaTipoItem, ColunaNCM, ColunaServico},
+			linhas:     [][]string{{"S1", "09", "96081000", "01.07"}},
+			esperados:  []string{"2 NCM SERV004"},
+		},
+		{
+			nome:       "código de serviço em mercadoria gera aviso",
+			tabela:     tabela,
+			cabecalhos: []string{ColunaCodigo, ColunaTipoItem, ColunaNCM, ColunaServico},
+			linhas:     [][]string{{"P1", "00", "96081000", "01.07"}, {"P2", "00", "96081000", ""}},
+			esperados:  []string{"2 Código Serviço SERV005"},
+		},
+		{
+			nome:       "sem a coluna de serviço o erro vai para o Tipo Item",
+			cabecalhos: []string{ColunaCodigo, ColunaTipoItem},
+			linhas:     [][]string{{"S1", "09"}, {"P1", "00"}},
+			esperados:  []string{"2 Tipo Item SERV001"},
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			v := novoValidatorTeste(caso.cabecalhos, caso.linhas...).ComServicos(caso.tabela)
+			conferirErros(t, v.validarServicos(), caso.esperados)
+		})
+	}
+}
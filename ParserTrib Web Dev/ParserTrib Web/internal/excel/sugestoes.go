@@ -0,0 +1,38 @@
+package excel
+
+import (
+	"ParserTrib/internal/domain"
+	"strings"
+)
+
+// sugestoesPorErro é a quantidade de NCMs candidatos anexados a cada erro
+const sugestoesPorErro = 3
+
+// sugerirNCM anexa aos erros da coluna NCM os códigos mais parecidos com a descrição da linha.
+// Não faz nada sem índice carregado ou sem coluna de descrição.
+func (v *Validator) sugerirNCM(erros []domain.ErroValidacao) {
+	if v.indiceNCM == nil {
+		return
+	}
+	indiceDescricao, ok := v.mapaIndices[ColunaDescricao]
+	if !ok {
+		return
+	}
+
+	for i := range erros {
+		if erros[i].NomeColuna != ColunaNCM {
+			continue
+		}
+
+		linha := v.rows[erros[i].Linha-1]
+		if indiceDescricao >= len(linha) {
+			continue
+		}
+		descricao := strings.TrimSpace(linha[indiceDescricao])
+		if descricao == "" {
+			continue
+		}
+
+		erros[i].Sugestoes = v.indiceNCM.Sugerir(descricao, sugestoesPorErro)
+	}
+}
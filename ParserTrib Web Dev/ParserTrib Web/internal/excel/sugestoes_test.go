@@ -0,0 +1,53 @@
+package excel
+
+import (
+	"ParserTrib/internal/domain"
+	"ParserTrib/internal/ncm"
+	"testing"
+)
+
+func TestSugerirNCM(t *testing.T) {
+	indice := ncm.NovoIndice([]ncm.Entrada{
+		{Codigo: "96081000", Descricao: "Canetas esferográficas"},
+		{Codigo: "96091000", Descricao: "Lápis"},
+	})
+	cabecalhos := []string{ColunaDescricao, ColunaNCM}
+	v := novoValidatorTeste(cabecalhos,
+		[]string{"CANETA ESFEROGRAFICA AZUL", "9608"},
+		[]string{"", "123"},
+		[]string{"LAPIS PRETO", "9609"},
+	).ComIndiceNCM(indice)
+
+	erros := []domain.ErroValidacao{
+		{Linha: 2, NomeColuna: ColunaNCM},
+		{Linha: 3, NomeColuna: ColunaNCM},
+		{Linha: 4, NomeColuna: ColunaDescricao},
+	}
+	v.sugerirNCM(erros)
+
+	if len(erros[0].Sugestoes) != 1 || erros[0].Sugestoes[0].NCM != "96081000" {
+		t.Errorf("sugestões da linha 2 = %+v, esperado 96081000", erros[0].Sugestoes)
+	}
+	if erros[1].Sugestoes != nil {
+		t.Errorf("linha sem descrição não deveria ter sugestões: %+v", erros[1].Sugestoes)
+	}
+	if erros[2].Sugestoes != nil {
+		t.Errorf("erro fora da coluna NCM não deveria ter sugestões: %+v", erros[2].Sugestoes)
+	}
+
+	t.Run("sem índice carregado", func(t *testing.T) {
+		erros := []domain.ErroValidacao{{Linha: 2, NomeColuna: ColunaNCM}}
+		novoValidatorTeste(cabecalhos, []string{"CANETA", "9608"}).sugerirNCM(erros)
+		if erros[0].Sugestoes != nil {
+			t.Errorf("sugestões = %+v, esperado nenhuma", erros[0].Sugestoes)
+		}
+	})
+
+	t.Run("sem coluna de descrição", func(t *testing.T) {
+		erros := []domain.ErroValidacao{{Linha: 2, NomeColuna: ColunaNCM}}
+		novoValidatorTeste([]string{ColunaNCM}, []string{"9608"}).ComIndiceNCM(indice).sugerirNCM(erros)
+		if erros[0].Sugestoes != nil {
+			t.Errorf("sugestões = %+v, esperado nenhuma", erros[0].Sugestoes)
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package excel
+
+// CodigoTabela é um valor permitido de uma tabela fiscal com a sua descrição
+type CodigoTabela struct {
+	Codigo    string
+	Descricao string
+}
+
+// TabelaCSOSN lista os CSOSN válidos conforme legislação do Simples Nacional
+var TabelaCSOSN = []CodigoTabela{
+	{"101", "Tributada pelo Simples Nacional com permissão de crédito"},
+	{"102", "Tributada pelo Simples Nacional sem permissão de crédito"},
+	{"103", "Isenção do ICMS no Simples Nacional para faixa de receita bruta"},
+	{"201", "Tributada pelo Simples Nacional com permissão de crédito e com cobrança do ICMS por ST"},
+	{"202", "Tributada pelo Simples Nacional sem permissão de crédito e com cobrança do ICMS por ST"},
+	{"203", "Isenção do ICMS no Simples Nacional para faixa de receita bruta e com cobrança do ICMS por ST"},
+	{"300", "Imune"},
+	{"400", "Não tributada pelo Simples Nacional"},
+	{"500", "ICMS cobrado anteriormente por substituição tributária ou por antecipação"},
+	{"900", "Outros"},
+}
+
+// TabelaCSTOrigem lista as origens da mercadoria (tabela A do CST)
+var TabelaCSTOrigem = []CodigoTabela{
+	{"0", "Nacional, exceto as indicadas nos códigos 3, 4, 5 e 8"},
+	{"1", "Estrangeira - importação direta, exceto a indicada no código 6"},
+	{"2", "Estrangeira - adquirida no mercado interno, exceto a indicada no código 7"},
+	{"3", "Nacional, com conteúdo de importação superior a 40% e inferior ou igual a 70%"},
+	{"4", "Nacional, produzida conforme os processos produtivos básicos (PPB)"},
+	{"5", "Nacional, com conteúdo de importação inferior ou igual a 40%"},
+	{"6", "Estrangeira - importação direta, sem similar nacional, constante em lista da CAMEX"},
+	{"7", "Estrangeira - adquirida no mercado interno, sem similar nacional, constante em lista da CAMEX"},
+	{"8", "Nacional, com conteúdo de importação superior a 70%"},
+}
+
+// TabelaTipoItem lista os tipos de item válidos conforme tabela fiscal
+var TabelaTipoItem = []CodigoTabela{
+	{"00", "Mercadoria para Revenda"},
+	{"01", "Matéria-Prima"},
+	{"02", "Embalagem"},
+	{"03", "Produto em Processo"},
+	{"04", "Produto Acabado"},
+	{"05", "Subproduto"},
+	{"06", "Produto Intermediário"},
+	{"07", "Material de Uso e Consumo"},
+	{"08", "Ativo Imobilizado"},
+	{"09", "Serviços"},
+	{"10", "Outros insumos"},
+	{"99", "Outras"},
+}
+
+// TabelaUnidades lista as unidades de medida aceitas quando não há tabela configurada
+// (ver CarregarTabelaUnidades)
+var TabelaUnidades = []CodigoTabela{
+	{"UN", "UNIDADE"},
+	{"UND", "UNIDADE"},
+	{"PC", "PECA"},
+	{"PCT", "PACOTE"},
+	{"CX", "CAIXA"},
+	{"KG", "QUILOGRAMA"},
+	{"G", "GRAMA"},
+	{"TON", "TONELADA"},
+	{"LT", "LITRO"},
+	{"L", "LITRO"},
+	{"ML", "MILILITRO"},
+	{"M", "METRO"},
+	{"M2", "METRO QUADRADO"},
+	{"M3", "METRO CUBICO"},
+	{"CM", "CENTIMETRO"},
+	{"DZ", "DUZIA"},
+	{"PAR", "PAR"},
+	{"JG", "JOGO"},
+	{"KIT", "KIT"},
+	{"CJ", "CONJUNTO"},
+	{"FD", "FARDO"},
+	{"RL", "ROLO"},
+	{"SC", "SACO"},
+	{"GL", "GALAO"},
+	{"FR", "FRASCO"},
+	{"BD", "BALDE"},
+	{"LATA", "LATA"},
+	{"TB", "TUBO"},
+	{"CT", "CARTELA"},
+	{"DISP", "DISPLAY"},
+}
+
+// mapaCodigos indexa os códigos de uma tabela para consulta rápida
+func mapaCodigos(tabela []CodigoTabela) map[string]bool {
+	mapa := make(map[string]bool, len(tabela))
+	for _, item := range tabela {
+		mapa[item.Codigo] = true
+	}
+	return mapa
+}
@@ -0,0 +1,228 @@
+package excel
+
+import (
+	"ParserTrib/internal/domain"
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// regexDecimalBR aceita números no formato pt-BR: vírgula decimal e ponto opcional de milhar
+// ("12", "0,5", "1.000", "1.000,25")
+var regexDecimalBR = regexp.MustCompile(`^(\d{1,3}(\.\d{3})+|\d+)(,\d+)?$`)
+
+// CarregarTabelaUnidades lê as unidades aceitas de um CSV com as colunas código e descrição
+// (separador ";" ou ","). Arquivo inexistente não é erro: devolve TabelaUnidades.
+func CarregarTabelaUnidades(caminho string) ([]CodigoTabela, error) {
+	tabela, err := lerTabelaCSV(caminho, "unidades", "UNIDADE")
+	if errors.Is(err, fs.ErrNotExist) {
+		return TabelaUnidades, nil
+	}
+	return tabela, err
+}
+
+// lerTabelaCSV lê uma tabela de códigos de um CSV com as colunas código e descrição (separador
+// ";" ou ","), ignorando a linha de cabeçalho. nome identifica a tabela nas mensagens de erro e
+// cabecalhos são os títulos aceitos, além de "código", para a primeira coluna.
+func lerTabelaCSV(caminho, nome string, cabecalhos ...string) ([]CodigoTabela, error) {
+	f, err := os.Open(caminho)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir tabela de %s: %w", nome, err)
+	}
+	defer f.Close()
+
+	leitor := bufio.NewReader(f)
+	primeiraLinha, _ := leitor.Peek(4096)
+
+	r := csv.NewReader(leitor)
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	if strings.Count(string(primeiraLinha), ";") > 0 {
+		r.Comma = ';'
+	}
+
+	cabecalhos = append(cabecalhos, "CODIGO", "CÓDIGO")
+	var tabela []CodigoTabela
+	for linha := 1; ; linha++ {
+		registro, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler tabela de %s: %w", nome, err)
+		}
+
+		codigo := strings.ToUpper(strings.TrimSpace(strings.TrimPrefix(registro[0], "\ufeff")))
+		if codigo == "" || (linha == 1 && slices.Contains(cabecalhos, codigo)) {
+			continue
+		}
+		item := CodigoTabela{Codigo: codigo}
+		if len(registro) > 1 {
+			item.Descricao = strings.TrimSpace(registro[1])
+		}
+		tabela = append(tabela, item)
+	}
+
+	if len(tabela) == 0 {
+		return nil, fmt.Errorf("tabela de %s '%s' está vazia", nome, caminho)
+	}
+	return tabela, nil
+}
+
+// ComUnidades define as unidades aceitas (sem tabela, vale TabelaUnidades)
+func (v *Validator) ComUnidades(tabela []CodigoTabela) *Validator {
+	if len(tabela) > 0 {
+		v.unidades = mapaCodigos(tabela)
+	}
+	return v
+}
+
+// validarUnidades verifica as unidades comercial e tributável contra a tabela, o fator de conversão
+// entre elas e, nas linhas com GTIN, se o mesmo GTIN usa sempre a mesma unidade tributável.
+// A coluna "Unidade" da planilha padrão guarda o código interno do ERP e fica de fora.
+func (v *Validator) validarUnidades() []domain.ErroValidacao {
+	var erros []domain.ErroValidacao
+
+	unidades := v.unidades
+	if unidades == nil {
+		unidades = mapaCodigos(TabelaUnidades)
+	}
+	indiceCom, temCom := v.mapaIndices[ColunaUnidadeComercial]
+	indiceTrib, temTrib := v.mapaIndices[ColunaUnidadeTributavel]
+	indiceFator, temFator := v.mapaIndices[ColunaFatorConversao]
+	indiceGTIN, temGTIN := v.mapaIndices[ColunaGTIN]
+
+	// GTIN → primeira linha e unidade tributável encontradas
+	type ocorrenciaGTIN struct {
+		linha   int
+		unidade string
+	}
+	porGTIN := make(map[string]ocorrenciaGTIN)
+
+	for i := 1; i < len(v.rows); i++ {
+		numLinha := i + 1
+
+		uCom, uTrib := "", ""
+		for _, coluna := range []struct {
+			indice  int
+			existe  bool
+			nome    string
+			unidade *string
+		}{
+			{indiceCom, temCom, ColunaUnidadeComercial, &uCom},
+			{indiceTrib, temTrib, ColunaUnidadeTributavel, &uTrib},
+		} {
+			if !coluna.existe {
+				continue
+			}
+			valor := strings.ToUpper(v.valorCelula(i, coluna.indice))
+			*coluna.unidade = valor
+			if valor != "" && !unidades[valor] {
+				erros = append(erros, domain.ErroValidacao{
+					Linha:      numLinha,
+					Coluna:     indiceParaLetra(coluna.indice),
+					NomeColuna: coluna.nome,
+					Codigo:     RegraUnidadeForaTabela,
+					Mensagem:   "UNIDADE INVÁLIDA - não consta na tabela de unidades (atual: '" + valor + "')",
+				})
+			}
+		}
+
+		// Fator de conversão: só faz sentido com as duas unidades preenchidas
+		if uCom != "" && uTrib != "" {
+			if erro, ok := v.verificarFator(i, uCom, uTrib, indiceFator, temFator, indiceTrib); ok {
+				erros = append(erros, erro)
+			}
+		}
+
+		if !temGTIN || uTrib == "" || v.linhaServico(i) {
+			continue
+		}
+		gtin := v.valorCelula(i, indiceGTIN)
+		if gtin == "" || strings.EqualFold(gtin, "SEM GTIN") {
+			continue
+		}
+		primeira, existe := porGTIN[gtin]
+		if !existe {
+			porGTIN[gtin] = ocorrenciaGTIN{numLinha, uTrib}
+			continue
+		}
+		if primeira.unidade != uTrib {
+			erros = append(erros, domain.ErroValidacao{
+				Linha:      numLinha,
+				Coluna:     indiceParaLetra(indiceTrib),
+				NomeColuna: ColunaUnidadeTributavel,
+				Codigo:     RegraUnidadeGTIN,
+				Mensagem: fmt.Sprintf("UNIDADE TRIBUTÁVEL DIVERGENTE PARA O MESMO GTIN - GTIN %s usa '%s' na linha %d (atual: '%s')",
+					gtin, primeira.unidade, primeira.linha, uTrib),
+			})
+		}
+	}
+
+	return erros
+}
+
+// verificarFator confere o fator de conversão da linha: decimal pt-BR positivo quando as unidades
+// diferem, exatamente 1 (ou vazio) quando são iguais
+func (v *Validator) verificarFator(i int, uCom, uTrib string, indiceFator int, temFator bool, indiceTrib int) (domain.ErroValidacao, bool) {
+	numLinha := i + 1
+	iguais := uCom == uTrib
+
+	if !temFator {
+		if iguais {
+			return domain.ErroValidacao{}, false
+		}
+		return domain.ErroValidacao{
+			Linha:      numLinha,
+			Coluna:     indiceParaLetra(indiceTrib),
+			NomeColuna: ColunaUnidadeTributavel,
+			Codigo:     RegraFatorConversao,
+			Mensagem:   "FATOR DE CONVERSÃO OBRIGATÓRIO - unidades diferentes ('" + uCom + "' e '" + uTrib + "'), mas a planilha não tem a coluna '" + ColunaFatorConversao + "'",
+		}, true
+	}
+
+	valor := v.valorCelula(i, indiceFator)
+	erro := domain.ErroValidacao{
+		Linha:      numLinha,
+		Coluna:     indiceParaLetra(indiceFator),
+		NomeColuna: ColunaFatorConversao,
+		Codigo:     RegraFatorConversao,
+	}
+
+	if valor == "" {
+		if iguais {
+			return domain.ErroValidacao{}, false
+		}
+		erro.Mensagem = "FATOR DE CONVERSÃO OBRIGATÓRIO - unidades diferentes ('" + uCom + "' e '" + uTrib + "')"
+		return erro, true
+	}
+
+	fator, ok := decimalBR(valor)
+	switch {
+	case !ok || fator <= 0:
+		erro.Mensagem = "FATOR DE CONVERSÃO INVÁLIDO - deve ser um número positivo com vírgula decimal (ex.: 12 ou 0,5) (atual: '" + valor + "')"
+		return erro, true
+	case iguais && fator != 1:
+		erro.Codigo = RegraFatorUnidadesIguais
+		erro.Mensagem = "FATOR DE CONVERSÃO DEVE SER 1 - unidade comercial e tributável são iguais ('" + uCom + "') (atual: '" + valor + "')"
+		return erro, true
+	}
+	return domain.ErroValidacao{}, false
+}
+
+// decimalBR converte um número no formato pt-BR ("1.000,25")
+func decimalBR(valor string) (float64, bool) {
+	if !regexDecimalBR.MatchString(valor) {
+		return 0, false
+	}
+	numero, err := strconv.ParseFloat(strings.ReplaceAll(strings.ReplaceAll(valor, ".", ""), ",", "."), 64)
+	return numero, err == nil
+}
@@ -0,0 +1,70 @@
+package excel
+
+import "testing"
+
+func TestValidarUnidades(t *testing.T) {
+	cabecalhos := []string{ColunaCodigo, ColunaUnidade, ColunaUnidadeComercial, ColunaUnidadeTributavel, ColunaFatorConversao, ColunaGTIN}
+
+	casos := []struct {
+		nome       string
+		tabela     []CodigoTabela
+		cabecalhos []string
+		linhas     [][]string
+		esperados  []string
+	}{
+		{
+			nome:       "código da unidade no ERP não é validado",
+			cabecalhos: cabecalhos,
+			linhas:     [][]string{{"P1", "1", "un", "UN", "", ""}, {"P2", "7", "CX", "UN", "12", ""}},
+		},
+		{
+			nome:       "unidades fora da tabela",
+			cabecalhos: cabecalhos,
+			linhas:     [][]string{{"P1", "1", "ZZ", "ZZ", "", ""}},
+			esperados:  []string{"2 Unidade Comercial UNID001", "2 Unidade Tributável UNID001"},
+		},
+		{
+			nome:       "tabela informada substitui a padrão",
+			tabela:     []CodigoTabela{{Codigo: "ZZ"}},
+			cabecalhos: cabecalhos,
+			linhas:     [][]string{{"P1", "1", "ZZ", "UN", "1", ""}},
+			esperados:  []string{"2 Unidade Tributável UNID001"},
+		},
+		{
+			nome:       "fator de conversão",
+			cabecalhos: cabecalhos,
+			linhas: [][]string{
+				{"P1", "1", "CX", "UN", "", ""},
+				{"P2", "1", "CX", "UN", "-2", ""},
+				{"P3", "1", "UN", "UN", "2", ""},
+				{"P4", "1", "CX", "KG", "0,5", ""},
+				{"P5", "1", "UN", "UN", "1,0", ""},
+			},
+			esperados: []string{"2 Fator de Conversão UNID002", "3 Fator de Conversão UNID002", "4 Fator de Conversão UNID003"},
+		},
+		{
+			nome:       "sem a coluna de fator o erro vai para a unidade tributável",
+			cabecalhos: []string{ColunaCodigo, ColunaUnidadeComercial, ColunaUnidadeTributavel},
+			linhas:     [][]string{{"P1", "CX", "UN"}, {"P2", "UN", "UN"}},
+			esperados:  []string{"2 Unidade Tributável UNID002"},
+		},
+		{
+			nome:       "mesmo GTIN com outra unidade tributável",
+			cabecalhos: cabecalhos,
+			linhas: [][]string{
+				{"P1", "1", "UN", "UN", "", "7891234567895"},
+				{"P2", "1", "KG", "KG", "", "7891234567895"},
+				{"P3", "1", "KG", "KG", "", "SEM GTIN"},
+				{"P4", "1", "UN", "UN", "", "SEM GTIN"},
+			},
+			esperados: []string{"3 Unidade Tributável UNID004"},
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			v := novoValidatorTeste(caso.cabecalhos, caso.linhas...).ComUnidades(caso.tabela)
+			conferirErros(t, v.validarUnidades(), caso.esperados)
+		})
+	}
+}
@@ -1,18 +1,54 @@
 package excel
 
 import (
+	"ParserTrib/internal/cbenef"
 	"ParserTrib/internal/domain"
+	"ParserTrib/internal/ncm"
+	"ParserTrib/internal/reforma"
+	"math"
 	"regexp"
 	"strconv"
 	"strings"
 )
 
+// regexNCM valida o formato do NCM: exatamente 8 dígitos
+var regexNCM = regexp.MustCompile(`^\d{8}$`)
+
+// colunasCondicionais só são obrigatórias conforme outra coluna; o vazio é verificado pela regra própria
+var colunasCondicionais = map[string]bool{
+	ColunaFCI:            true, // exigida apenas para as origens 3, 5 e 8 (ver validarFCI)
+	ColunaCBenef:         true, // exigido apenas para alguns CSTs, conforme a UF (ver validarCBenef)
+	ColunaFatorConversao: true, // exigido apenas quando as unidades diferem (ver validarUnidades)
+	ColunaServico:        true, // exigido apenas para Tipo Item 09 (ver validarServicos)
+	ColunaANP:            true, // exigido apenas para NCM de combustível (ver validarCombustiveis)
+}
+
+// CSOSN e Tipos de item válidos (ver tabelas.go)
+var (
+	csosnValidos = mapaCodigos(TabelaCSOSN)
+	tiposValidos = mapaCodigos(TabelaTipoItem)
+)
+
 // Validator valida dados da planilha Excel
 type Validator struct {
 	rows        [][]string
 	sheetName   string
 	cabecalhos  []string
 	mapaIndices map[string]int
+	metadados   [][]domain.MetadadoCelula
+	indiceNCM   *ncm.Indice
+	perfil      *PerfilRegras
+	colunaChave string
+	itensEFD    []domain.Item0200
+	beneficios  *cbenef.Tabela
+	uf          string
+	unidades    map[string]bool
+	servicos    map[string]bool
+
+	prefixosCombustivel []string
+	produtosANP         map[string]string // código ANP → descrição
+
+	tabelaReforma *reforma.Tabela
 }
 
 // NovoValidator cria instância do validador
@@ -27,20 +63,101 @@ func NovoValidator(rows [][]string, sheetName string, cabecalhos []string) *Vali
 		sheetName:   sheetName,
 		cabecalhos:  cabecalhos,
 		mapaIndices: mapaIndices,
+		colunaChave: ColunaCodigo,
 	}
 }
 
-// ValidarTudo executa as 4 validações
+// ComMetadados informa o tipo e o formato de cada célula (ver Reader.ObterMetadadosCelulas),
+// habilitando as regras que dependem de como o valor está armazenado no Excel
+func (v *Validator) ComMetadados(metadados [][]domain.MetadadoCelula) *Validator {
+	v.metadados = metadados
+	return v
+}
+
+// ComIndiceNCM habilita as sugestões de NCM (a partir da descrição) nos erros da coluna NCM
+func (v *Validator) ComIndiceNCM(indice *ncm.Indice) *Validator {
+	v.indiceNCM = indice
+	return v
+}
+
+// ComPerfilRegras aplica as severidades configuradas no perfil (sem perfil, valem as severidades padrão)
+func (v *Validator) ComPerfilRegras(perfil *PerfilRegras) *Validator {
+	v.perfil = perfil
+	return v
+}
+
+// ComColunaChave define a coluna que identifica o produto em cada erro (padrão: "Código"),
+// usada pelo baseline de supressões e pela comparação entre versões da planilha
+func (v *Validator) ComColunaChave(coluna string) *Validator {
+	if coluna != "" {
+		v.colunaChave = coluna
+	}
+	return v
+}
+
+// ValidarTudo executa todas as validações
 func (v *Validator) ValidarTudo(totalLinhas int) domain.ResultadoValidacaoCompleto {
-	return domain.ResultadoValidacaoCompleto{
-		ErrosVazias:    v.validarVazias(),
-		ErrosNCM:       v.validarNCM(),
-		ErrosCSTOrigem: v.validarCSTOrigem(),
-		ErrosCSOSN:     v.validarCSOSN(),
-		ErrosTipoItem:  v.validarTipoItem(),
+	errosVazias := v.validarVazias()
+	errosNCM := v.validarNCM()
+	v.sugerirNCM(errosVazias)
+	v.sugerirNCM(errosNCM)
+
+	resultado := domain.ResultadoValidacaoCompleto{
+		ErrosVazias:         errosVazias,
+		ErrosNCM:            errosNCM,
+		ErrosCSTOrigem:      v.validarCSTOrigem(),
+		ErrosCSOSN:          v.validarCSOSN(),
+		ErrosTipoItem:       v.validarTipoItem(),
+		ErrosFCI:            v.validarFCI(),
+		ErrosCBenef:         v.validarCBenef(),
+		ErrosUnidade:        v.validarUnidades(),
+		ErrosServico:        v.validarServicos(),
+		ErrosANP:            v.validarCombustiveis(),
+		ErrosReforma:        v.validarReforma(),
+		ErrosTexto:          v.validarTexto(),
+		ErrosCelulaNumerica: v.validarCelulasNumericas(),
+		ErrosEFD:            v.conciliarEFD(),
+		Perfil:              v.perfilar(),
+		Cobertura:           v.calcularCobertura(),
+		Duplicados:          v.detectarDuplicados(),
+		AvisosConsistencia:  v.verificarConsistencia(),
+		TotalLinhas:         totalLinhas,
+	}
+	v.perfil.aplicarSeveridades(&resultado)
+	v.preencherChaves(&resultado)
+
+	return resultado
+}
+
+// preencherChaves copia para cada erro e aviso o valor da coluna-chave da sua linha
+func (v *Validator) preencherChaves(resultado *domain.ResultadoValidacaoCompleto) {
+	indice, ok := v.mapaIndices[v.colunaChave]
+	if !ok {
+		return
+	}
+
+	chaveDa := func(linha int) string {
+		if linha < 1 || linha-1 >= len(v.rows) || indice >= len(v.rows[linha-1]) {
+			return ""
+		}
+		return strings.TrimSpace(v.rows[linha-1][indice])
+	}
+
+	for _, categoria := range resultado.Categorias() {
+		for i := range categoria.Erros {
+			// Problemas fora da planilha (linha 0) já trazem a própria chave
+			if categoria.Erros[i].Chave == "" {
+				categoria.Erros[i].Chave = chaveDa(categoria.Erros[i].Linha)
+			}
+		}
+	}
+	for i := range resultado.AvisosConsistencia {
+		resultado.AvisosConsistencia[i].Chave = chaveDa(resultado.AvisosConsistencia[i].Linha)
 	}
 }
 
+// validarVazias aponta as células vazias que precisavam estar preenchidas: todas as das colunas
+// obrigatórias e, nas condicionais, as das linhas que atendem à condição (ver regraColuna)
 func (v *Validator) validarVazias() []domain.ErroValidacao {
 	var erros []domain.ErroValidacao
 
@@ -49,10 +166,16 @@ func (v *Validator) validarVazias() []domain.ErroValidacao {
 	}
 
 	maxColunas := len(v.cabecalhos)
+	regras := make([]RegraColuna, maxColunas)
+	verificadas := make([]bool, maxColunas)
+	for j, nome := range v.cabecalhos {
+		regras[j], verificadas[j] = v.regraColuna(nome)
+	}
 
 	for i := 1; i < len(v.rows); i++ {
 		linha := v.rows[i]
 		numLinha := i + 1
+		servico := v.linhaServico(i)
 
 		for j := 0; j < maxColunas; j++ {
 			celula := ""
@@ -60,7 +183,7 @@ func (v *Validator) validarVazias() []domain.ErroValidacao {
 				celula = strings.TrimSpace(linha[j])
 			}
 
-			if celula == "" {
+			if celula == "" && verificadas[j] && v.celulaExigida(i, j, regras[j], servico) {
 				colLetra := indiceParaLetra(j)
 				nomeColuna := v.cabecalhos[j]
 
@@ -68,6 +191,7 @@ func (v *Validator) validarVazias() []domain.ErroValidacao {
 					Linha:      numLinha,
 					Coluna:     colLetra,
 					NomeColuna: nomeColuna,
+					Codigo:     RegraCelulaVazia,
 					Mensagem:   "CÉLULA VAZIA",
 				})
 			}
@@ -81,13 +205,11 @@ func (v *Validator) validarVazias() []domain.ErroValidacao {
 func (v *Validator) validarNCM() []domain.ErroValidacao {
 	var erros []domain.ErroValidacao
 
-	indiceNCM, existe := v.mapaIndices["NCM"]
+	indiceNCM, existe := v.mapaIndices[ColunaNCM]
 	if !existe {
 		return erros
 	}
 
-	regexNCM := regexp.MustCompile(`^\d{8}$`)
-
 	for i := 1; i < len(v.rows); i++ {
 		linha := v.rows[i]
 		numLinha := i + 1
@@ -97,14 +219,15 @@ func (v *Validator) validarNCM() []domain.ErroValidacao {
 			valorNCM = strings.TrimSpace(linha[indiceNCM])
 		}
 
-		if valorNCM == "" {
-			// Já reportado por validarVazias — pular
+		if valorNCM == "" || v.linhaServico(i) {
+			// Vazio já reportado por validarVazias; serviços são verificados por validarServicos
 			continue
 		} else if !regexNCM.MatchString(valorNCM) {
 			erros = append(erros, domain.ErroValidacao{
 				Linha:      numLinha,
 				Coluna:     indiceParaLetra(indiceNCM),
-				NomeColuna: "NCM",
+				NomeColuna: ColunaNCM,
+				Codigo:     RegraNCMFormato,
 				Mensagem:   "NCM INVÁLIDO - deve conter exatamente 8 dígitos numéricos (atual: '" + valorNCM + "')",
 			})
 		}
@@ -117,7 +240,7 @@ func (v *Validator) validarNCM() []domain.ErroValidacao {
 func (v *Validator) validarCSTOrigem() []domain.ErroValidacao {
 	var erros []domain.ErroValidacao
 
-	indiceCST, existe := v.mapaIndices["CST Origem"]
+	indiceCST, existe := v.mapaIndices[ColunaCSTOrigem]
 	if !existe {
 		return erros
 	}
@@ -140,14 +263,16 @@ func (v *Validator) validarCSTOrigem() []domain.ErroValidacao {
 				erros = append(erros, domain.ErroValidacao{
 					Linha:      numLinha,
 					Coluna:     indiceParaLetra(indiceCST),
-					NomeColuna: "CST Origem",
+					NomeColuna: ColunaCSTOrigem,
+					Codigo:     RegraCSTOrigemNaoNumerico,
 					Mensagem:   "CST ORIGEM INVÁLIDO - deve ser um número entre 0 e 8 (atual: '" + valorCST + "')",
 				})
 			} else if num < 0 || num > 8 {
 				erros = append(erros, domain.ErroValidacao{
 					Linha:      numLinha,
 					Coluna:     indiceParaLetra(indiceCST),
-					NomeColuna: "CST Origem",
+					NomeColuna: ColunaCSTOrigem,
+					Codigo:     RegraCSTOrigemFaixa,
 					Mensagem:   "CST ORIGEM FORA DO RANGE - deve estar entre 0 e 8 (atual: " + valorCST + ")",
 				})
 			}
@@ -161,25 +286,11 @@ func (v *Validator) validarCSTOrigem() []domain.ErroValidacao {
 func (v *Validator) validarCSOSN() []domain.ErroValidacao {
 	var erros []domain.ErroValidacao
 
-	indiceCSOSN, existe := v.mapaIndices["CSOSN"]
+	indiceCSOSN, existe := v.mapaIndices[ColunaCSOSN]
 	if !existe {
 		return erros
 	}
 
-	// CSOSN válidos conforme legislação do Simples Nacional
-	csosnValidos := map[string]bool{
-		"101": true, // Tributada pelo Simples Nacional com permissão de crédito
-		"102": true, // Tributada pelo Simples Nacional sem permissão de crédito
-		"103": true, // Isenção do ICMS no Simples Nacional para faixa de receita bruta
-		"201": true, // Tributada pelo Simples Nacional com permissão de crédito e com cobrança do ICMS por ST
-		"202": true, // Tributada pelo Simples Nacional sem permissão de crédito e com cobrança do ICMS por ST
-		"203": true, // Isenção do ICMS no Simples Nacional para faixa de receita bruta e com cobrança do ICMS por ST
-		"300": true, // Imune
-		"400": true, // Não tributada pelo Simples Nacional
-		"500": true, // ICMS cobrado anteriormente por substituição tributária ou por antecipação
-		"900": true, // Outros
-	}
-
 	for i := 1; i < len(v.rows); i++ {
 		linha := v.rows[i]
 		numLinha := i + 1
@@ -196,7 +307,8 @@ func (v *Validator) validarCSOSN() []domain.ErroValidacao {
 			erros = append(erros, domain.ErroValidacao{
 				Linha:      numLinha,
 				Coluna:     indiceParaLetra(indiceCSOSN),
-				NomeColuna: "CSOSN",
+				NomeColuna: ColunaCSOSN,
+				Codigo:     RegraCSOSNInvalido,
 				Mensagem:   "CSOSN INVÁLIDO - deve ser um dos códigos válidos: 101, 102, 103, 201, 202, 203, 300, 400, 500, 900 (atual: '" + valorCSOSN + "')",
 			})
 		}
@@ -209,27 +321,11 @@ func (v *Validator) validarCSOSN() []domain.ErroValidacao {
 func (v *Validator) validarTipoItem() []domain.ErroValidacao {
 	var erros []domain.ErroValidacao
 
-	indiceTipoItem, existe := v.mapaIndices["Tipo Item"]
+	indiceTipoItem, existe := v.mapaIndices[ColunaTipoItem]
 	if !existe {
 		return erros
 	}
 
-	// Tipos de item válidos conforme tabela fiscal
-	tiposValidos := map[string]bool{
-		"00": true, // Mercadoria para Revenda
-		"01": true, // Matéria-Prima
-		"02": true, // Embalagem
-		"03": true, // Produto em Processo
-		"04": true, // Produto Acabado
-		"05": true, // Subproduto
-		"06": true, // Produto Intermediário
-		"07": true, // Material de Uso e Consumo
-		"08": true, // Ativo Imobilizado
-		"09": true, // Serviços
-		"10": true, // Outros insumos
-		"99": true, // Outras
-	}
-
 	for i := 1; i < len(v.rows); i++ {
 		linha := v.rows[i]
 		numLinha := i + 1
@@ -249,7 +345,8 @@ func (v *Validator) validarTipoItem() []domain.ErroValidacao {
 				erros = append(erros, domain.ErroValidacao{
 					Linha:      numLinha,
 					Coluna:     indiceParaLetra(indiceTipoItem),
-					NomeColuna: "Tipo Item",
+					NomeColuna: ColunaTipoItem,
+					Codigo:     RegraTipoItemNaoNumerico,
 					Mensagem:   "TIPO ITEM INVÁLIDO - deve ser um número inteiro (atual: '" + valorTipoItem + "')",
 				})
 			} else if !tiposValidos[valorTipoItem] {
@@ -257,7 +354,8 @@ func (v *Validator) validarTipoItem() []domain.ErroValidacao {
 				erros = append(erros, domain.ErroValidacao{
 					Linha:      numLinha,
 					Coluna:     indiceParaLetra(indiceTipoItem),
-					NomeColuna: "Tipo Item",
+					NomeColuna: ColunaTipoItem,
+					Codigo:     RegraTipoItemForaTabela,
 					Mensagem:   "TIPO ITEM FORA DA TABELA - deve ser um dos códigos válidos: 00, 01, 02, 03, 04, 05, 06, 07, 08, 09, 10, 99 (atual: '" + valorTipoItem + "')",
 				})
 			}
@@ -267,6 +365,98 @@ func (v *Validator) validarTipoItem() []domain.ErroValidacao {
 	return erros
 }
 
+// colunasCodigo são as colunas que guardam códigos e devem ser texto no Excel.
+// largura é o tamanho fixo do código, usado para recuperar zeros à esquerda (0 = variável)
+var colunasCodigo = []struct {
+	nome    string
+	largura int
+}{
+	{ColunaNCM, 8},
+	{ColunaCEST, 7},
+	{ColunaGTIN, 0},
+	{ColunaTipoItem, 2},
+	{ColunaCSTOrigem, 0},
+}
+
+// regexNotacaoCientifica reconhece valores exibidos como 7.89E+12
+var regexNotacaoCientifica = regexp.MustCompile(`(?i)^-?[\d.,]+e[+-]?\d+$`)
+
+// validarCelulasNumericas verifica se as colunas de código estão armazenadas como número,
+// o que faz o Excel descartar zeros à esquerda ou exibir o código em notação científica
+func (v *Validator) validarCelulasNumericas() []domain.ErroValidacao {
+	var erros []domain.ErroValidacao
+
+	if v.metadados == nil {
+		return erros
+	}
+
+	for _, coluna := range colunasCodigo {
+		indice, existe := v.mapaIndices[coluna.nome]
+		if !existe {
+			continue
+		}
+
+		for i := 1; i < len(v.rows) && i < len(v.metadados); i++ {
+			if indice >= len(v.metadados[i]) || v.metadados[i][indice].Tipo != domain.CelulaNumero {
+				continue
+			}
+			if (coluna.nome == ColunaCEST || coluna.nome == ColunaGTIN) && v.linhaServico(i) {
+				continue
+			}
+			meta := v.metadados[i][indice]
+
+			exibido := ""
+			if indice < len(v.rows[i]) {
+				exibido = strings.TrimSpace(v.rows[i][indice])
+			}
+
+			codigo := RegraCodigoNumerico
+			mensagem := "CÓDIGO ARMAZENADO COMO NÚMERO - o Excel descarta zeros à esquerda de células numéricas; formate a coluna como Texto"
+			if regexNotacaoCientifica.MatchString(exibido) || strings.Contains(strings.ToUpper(meta.FormatoNumero), "E+") {
+				codigo = RegraCodigoNotacaoCientifica
+				mensagem = "CÓDIGO EM NOTAÇÃO CIENTÍFICA - a célula é numérica e o Excel exibe o código abreviado; formate a coluna como Texto"
+			}
+
+			if recuperado, ok := recuperarCodigo(meta.ValorBruto, coluna.largura); ok {
+				mensagem += " (exibido: '" + exibido + "', valor recuperado: '" + recuperado + "')"
+			} else {
+				mensagem += " (exibido: '" + exibido + "', não foi possível recuperar o valor original — os dígitos podem ter sido perdidos)"
+			}
+
+			erros = append(erros, domain.ErroValidacao{
+				Linha:      i + 1,
+				Coluna:     indiceParaLetra(indice),
+				NomeColuna: coluna.nome,
+				Codigo:     codigo,
+				Mensagem:   mensagem,
+			})
+		}
+	}
+
+	return erros
+}
+
+// recuperarCodigo reconstrói o código a partir do valor numérico bruto da célula,
+// completando com zeros à esquerda até a largura informada. Não recupera valores que já
+// foram gravados em notação científica ou com casas decimais, pois os dígitos se perderam.
+func recuperarCodigo(bruto string, largura int) (string, bool) {
+	bruto = strings.TrimSpace(bruto)
+	if bruto == "" || strings.ContainsAny(bruto, "eE") {
+		return "", false
+	}
+
+	numero, err := strconv.ParseFloat(bruto, 64)
+	if err != nil || numero < 0 || numero != math.Trunc(numero) || numero > 1e15 {
+		return "", false
+	}
+
+	codigo := strconv.FormatFloat(numero, 'f', 0, 64)
+	if len(codigo) < largura {
+		codigo = strings.Repeat("0", largura-len(codigo)) + codigo
+	}
+	return codigo, true
+}
+
 // indiceParaLetra converte índice numérico para letra Excel (0=A, 1=B, 26=AA)
 func indiceParaLetra(indice int) string {
 	letra := ""
@@ -0,0 +1,223 @@
+package excel
+
+import (
+	"ParserTrib/internal/domain"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// novoValidatorTeste monta um validador com o cabeçalho e as linhas de dados informados
+func novoValidatorTeste(cabecalhos []string, linhas ...[]string) *Validator {
+	rows := append([][]string{cabecalhos}, linhas...)
+	return NovoValidator(rows, "Produto", cabecalhos)
+}
+
+// resumirErros descreve cada erro como "linha coluna código", para comparar com os casos esperados
+func resumirErros(erros []domain.ErroValidacao) []string {
+	var resumo []string
+	for _, e := range erros {
+		resumo = append(resumo, fmt.Sprintf("%d %s %s", e.Linha, e.NomeColuna, e.Codigo))
+	}
+	return resumo
+}
+
+func conferirErros(t *testing.T, obtidos []domain.ErroValidacao, esperados []string) {
+	t.Helper()
+	if resumo := resumirErros(obtidos); !slices.Equal(resumo, esperados) {
+		t.Errorf("erros = %q, esperado %q", resumo, esperados)
+	}
+}
+
+func TestValidarVazias(t *testing.T) {
+	casos := []struct {
+		nome       string
+		perfil     *PerfilRegras
+		cabecalhos []string
+		linhas     [][]string
+		esperados  []string
+	}{
+		{
+			nome:       "coluna obrigatória por padrão",
+			cabecalhos: []string{ColunaCodigo, ColunaDescricao},
+			linhas:     [][]string{{"P1", ""}, {"P2", "Caneta"}},
+			esperados:  []string{"2 Descrição VAZ001"},
+		},
+		{
+			nome:       "colunas com regra própria ficam de fora",
+			cabecalhos: []string{ColunaCodigo, ColunaFCI, ColunaANP, ColunaServico},
+			linhas:     [][]string{{"P1", "", "", ""}},
+		},
+		{
+			nome:       "colunas da reforma só com as regras habilitadas",
+			cabecalhos: []string{ColunaCodigo, ColunaCSTIBSCBS, ColunaCClassTrib},
+			linhas:     [][]string{{"P1", "", ""}},
+		},
+		{
+			nome:       "colunas da reforma exigidas com as regras habilitadas",
+			perfil:     &PerfilRegras{Habilitar: []string{RegraCSTIBSCBS}},
+			cabecalhos: []string{ColunaCodigo, ColunaCSTIBSCBS, ColunaCClassTrib},
+			linhas:     [][]string{{"P1", "", "000001"}},
+			esperados:  []string{"2 CST IBS/CBS VAZ001"},
+		},
+		{
+			nome:       "opcional no perfil",
+			perfil:     &PerfilRegras{Colunas: map[string]RegraColuna{"Referência": {Obrigatoriedade: domain.ColunaOpcional}}},
+			cabecalhos: []string{ColunaCodigo, "Referência"},
+			linhas:     [][]string{{"P1", ""}},
+		},
+		{
+			nome: "condicional no perfil",
+			perfil: &PerfilRegras{Colunas: map[string]RegraColuna{
+				ColunaCEST: {Obrigatoriedade: domain.ColunaCondicional, Coluna: ColunaTipoItem, Valores: []string{"00"}},
+			}},
+			cabecalhos: []string{ColunaCodigo, ColunaTipoItem, ColunaCEST},
+			linhas:     [][]string{{"P1", "00", ""}, {"P2", "04", ""}},
+			esperados:  []string{"2 CEST VAZ001"},
+		},
+		{
+			nome:       "linha de serviço dispensa NCM e GTIN",
+			cabecalhos: []string{ColunaCodigo, ColunaTipoItem, ColunaNCM, ColunaGTIN},
+			linhas:     [][]string{{"S1", TipoItemServico, "", ""}, {"P1", "00", "", "SEM GTIN"}},
+			esperados:  []string{"3 NCM VAZ001"},
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			v := novoValidatorTeste(caso.cabecalhos, caso.linhas...).ComPerfilRegras(caso.perfil)
+			conferirErros(t, v.validarVazias(), caso.esperados)
+		})
+	}
+}
+
+func TestRecuperarCodigo(t *testing.T) {
+	casos := []struct {
+		bruto    string
+		largura  int
+		esperado string
+		ok       bool
+	}{
+		{"1012100", 8, "01012100", true},
+		{"76101000", 8, "76101000", true},
+		{"4", 2, "04", true},
+		{"7891234567895", 0, "7891234567895", true},
+		{"1012100.0", 8, "01012100", true},
+		{"7.89123456789E+12", 0, "", false},
+		{"1012100.5", 8, "", false},
+		{"-4", 2, "", false},
+		{"abc", 8, "", false},
+		{"", 8, "", false},
+	}
+
+	for _, caso := range casos {
+		recuperado, ok := recuperarCodigo(caso.bruto, caso.largura)
+		if recuperado != caso.esperado || ok != caso.ok {
+			t.Errorf("recuperarCodigo(%q, %d) = %q, %v; esperado %q, %v", caso.bruto, caso.largura, recuperado, ok, caso.esperado, caso.ok)
+		}
+	}
+}
+
+func TestValidarCelulasNumericas(t *testing.T) {
+	numero := func(bruto, formato string) domain.MetadadoCelula {
+		return domain.MetadadoCelula{Tipo: domain.CelulaNumero, FormatoNumero: formato, ValorBruto: bruto}
+	}
+	texto := domain.MetadadoCelula{Tipo: domain.CelulaTexto, FormatoNumero: "@"}
+
+	casos := []struct {
+		nome      string
+		exibido   string
+		meta      domain.MetadadoCelula
+		codigo    string
+		mensagens []string
+	}{
+		{nome: "código gravado como texto", exibido: "01012100", meta: texto},
+		{
+			nome:      "número sem o zero à esquerda",
+			exibido:   "1012100",
+			meta:      numero("1012100", "General"),
+			codigo:    RegraCodigoNumerico,
+			mensagens: []string{"CÓDIGO ARMAZENADO COMO NÚMERO", "valor recuperado: '01012100'"},
+		},
+		{
+			nome:      "número exibido em notação científica",
+			exibido:   "1.01E+06",
+			meta:      numero("1012100", "0.00E+00"),
+			codigo:    RegraCodigoNotacaoCientifica,
+			mensagens: []string{"CÓDIGO EM NOTAÇÃO CIENTÍFICA", "valor recuperado: '01012100'"},
+		},
+		{
+			nome:      "dígitos já perdidos no valor gravado",
+			exibido:   "1.01E+06",
+			meta:      numero("1.0121E+6", "General"),
+			codigo:    RegraCodigoNotacaoCientifica,
+			mensagens: []string{"CÓDIGO EM NOTAÇÃO CIENTÍFICA", "não foi possível recuperar o valor original"},
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			cabecalhos := []string{"Código", ColunaNCM}
+			v := novoValidatorTeste(cabecalhos, []string{"P1", caso.exibido}).
+				ComMetadados([][]domain.MetadadoCelula{{}, {texto, caso.meta}})
+
+			erros := v.validarCelulasNumericas()
+			if caso.codigo == "" {
+				conferirErros(t, erros, nil)
+				return
+			}
+			conferirErros(t, erros, []string{"2 NCM " + caso.codigo})
+			for _, trecho := range caso.mensagens {
+				if len(erros) == 1 && !strings.Contains(erros[0].Mensagem, trecho) {
+					t.Errorf("mensagem %q sem o trecho %q", erros[0].Mensagem, trecho)
+				}
+			}
+		})
+	}
+
+	t.Run("sem metadados nada é verificado", func(t *testing.T) {
+		conferirErros(t, novoValidatorTeste([]string{ColunaNCM}, []string{"1012100"}).validarCelulasNumericas(), nil)
+	})
+}
+
+func TestObterMetadadosCelulas(t *testing.T) {
+	caminho := filepath.Join(t.TempDir(), "produtos.xlsx")
+	f := excelize.NewFile()
+	f.SetSheetName("Sheet1", "Produto")
+	f.SetSheetRow("Produto", "A1", &[]any{"Código", ColunaNCM, ColunaTipoItem})
+	f.SetCellStr("Produto", "A2", "P1")
+	f.SetCellInt("Produto", "B2", 1012100)
+	f.SetCellStr("Produto", "C2", "04")
+	if err := f.SaveAs(caminho); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	reader, err := NovoReader(caminho, "Produto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	rows, err := reader.ObterTodasLinhas()
+	if err != nil {
+		t.Fatal(err)
+	}
+	metadados, err := reader.ObterMetadadosCelulas(rows)
+	if err != nil {
+		t.Fatalf("ObterMetadadosCelulas: %v", err)
+	}
+
+	if meta := metadados[1][1]; meta.Tipo != domain.CelulaNumero || meta.ValorBruto != "1012100" {
+		t.Errorf("metadado de B2 = %+v, esperado número com valor bruto 1012100", meta)
+	}
+	if meta := metadados[1][2]; meta.Tipo != domain.CelulaTexto {
+		t.Errorf("metadado de C2 = %+v, esperado texto", meta)
+	}
+
+	erros := NovoValidator(rows, "Produto", rows[0]).ComMetadados(metadados).validarCelulasNumericas()
+	conferirErros(t, erros, []string{"2 NCM NUM001"})
+}
@@ -2,6 +2,7 @@ package formatter
 
 import (
 	"ParserTrib/internal/domain"
+	"fmt"
 	"sort"
 	"strconv"
 	"strings"
@@ -36,93 +37,232 @@ func (f *Formatter) OrdenarErros(erros []domain.ErroValidacao) {
 	})
 }
 
-// FormatarSaida formata a saída completa com as 4 seções separadas
+// FormatarSaida formata a saída completa com uma seção por categoria de erro
 func (f *Formatter) FormatarSaida(resultado domain.ResultadoValidacaoCompleto) string {
 	var sb strings.Builder
 
-	// SEÇÃO 1: CÉLULAS VAZIAS
-	if len(resultado.ErrosVazias) > 0 {
+	for _, categoria := range resultado.Categorias() {
+		if len(categoria.Erros) == 0 {
+			if categoria.Tipo == domain.TipoVazia {
+				sb.WriteString("\n✓ Nenhuma célula vazia encontrada!\n")
+			}
+			continue
+		}
+
 		sb.WriteString("\n")
 		sb.WriteString(strings.Repeat("=", 60))
 		sb.WriteString("\n")
-		sb.WriteString("--- CÉLULAS VAZIAS (")
-		sb.WriteString(formatarNumero(len(resultado.ErrosVazias)))
+		sb.WriteString("--- ")
+		sb.WriteString(categoria.Titulo)
+		sb.WriteString(" (")
+		sb.WriteString(formatarNumero(len(categoria.Erros)))
 		sb.WriteString(") ---\n")
 		sb.WriteString(strings.Repeat("=", 60))
 		sb.WriteString("\n")
 
-		for _, erro := range resultado.ErrosVazias {
+		for _, erro := range categoria.Erros {
 			sb.WriteString(erro.String())
 			sb.WriteString("\n")
 		}
-	} else {
-		sb.WriteString("\n✓ Nenhuma célula vazia encontrada!\n")
 	}
 
-	// SEÇÃO 2: ERROS DE VALIDAÇÃO NCM
-	if len(resultado.ErrosNCM) > 0 {
-		sb.WriteString("\n")
-		sb.WriteString(strings.Repeat("=", 60))
+	return sb.String()
+}
+
+// FormatarPerfil formata a seção com o perfil de cada coluna
+func (f *Formatter) FormatarPerfil(perfis []domain.PerfilColuna) string {
+	var sb strings.Builder
+
+	if len(perfis) == 0 {
+		return ""
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(strings.Repeat("=", 60))
+	sb.WriteString("\n")
+	sb.WriteString("--- PERFIL DAS COLUNAS (")
+	sb.WriteString(formatarNumero(len(perfis)))
+	sb.WriteString(") ---\n")
+	sb.WriteString(strings.Repeat("=", 60))
+	sb.WriteString("\n")
+
+	for _, perfil := range perfis {
+		sb.WriteString(perfil.String())
 		sb.WriteString("\n")
-		sb.WriteString("--- ERROS DE VALIDAÇÃO NCM (")
-		sb.WriteString(formatarNumero(len(resultado.ErrosNCM)))
-		sb.WriteString(") ---\n")
-		sb.WriteString(strings.Repeat("=", 60))
+	}
+
+	return sb.String()
+}
+
+// FormatarCobertura formata a seção com o preenchimento das colunas exigidas e o total
+func (f *Formatter) FormatarCobertura(resultado domain.ResultadoValidacaoCompleto) string {
+	var sb strings.Builder
+
+	if len(resultado.Cobertura) == 0 {
+		return ""
+	}
+
+	preenchidas, exigidas, percentual := resultado.CoberturaObrigatorias()
+	sb.WriteString("\n")
+	sb.WriteString(strings.Repeat("=", 60))
+	sb.WriteString("\n")
+	sb.WriteString(fmt.Sprintf("--- COBERTURA DOS CAMPOS OBRIGATÓRIOS: %.1f%% (%s de %s células) ---\n",
+		percentual, formatarNumero(preenchidas), formatarNumero(exigidas)))
+	sb.WriteString(strings.Repeat("=", 60))
+	sb.WriteString("\n")
+
+	for _, coluna := range resultado.Cobertura {
+		sb.WriteString(coluna.String())
 		sb.WriteString("\n")
+	}
 
-		for _, erro := range resultado.ErrosNCM {
-			sb.WriteString(erro.String())
-			sb.WriteString("\n")
-		}
+	return sb.String()
+}
+
+// FormatarDuplicados formata a seção com os grupos de produtos duplicados (divergentes primeiro)
+func (f *Formatter) FormatarDuplicados(grupos []domain.GrupoDuplicado) string {
+	var sb strings.Builder
+
+	if len(grupos) == 0 {
+		return ""
 	}
 
-	// SEÇÃO 3: ERROS DE VALIDAÇÃO CST ORIGEM
-	if len(resultado.ErrosCSTOrigem) > 0 {
+	sb.WriteString("\n")
+	sb.WriteString(strings.Repeat("=", 60))
+	sb.WriteString("\n")
+	sb.WriteString("--- PRODUTOS DUPLICADOS (")
+	sb.WriteString(formatarNumero(len(grupos)))
+	sb.WriteString(" grupos) ---\n")
+	sb.WriteString(strings.Repeat("=", 60))
+	sb.WriteString("\n")
+
+	for _, grupo := range grupos {
+		sb.WriteString(grupo.String())
 		sb.WriteString("\n")
-		sb.WriteString(strings.Repeat("=", 60))
+	}
+
+	return sb.String()
+}
+
+// FormatarAvisos formata a seção de avisos de consistência (não contam como erro)
+func (f *Formatter) FormatarAvisos(avisos []domain.ErroValidacao) string {
+	var sb strings.Builder
+
+	if len(avisos) == 0 {
+		return ""
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(strings.Repeat("=", 60))
+	sb.WriteString("\n")
+	sb.WriteString("--- AVISOS DE CONSISTÊNCIA ENTRE PRODUTOS SEMELHANTES (")
+	sb.WriteString(formatarNumero(len(avisos)))
+	sb.WriteString(") ---\n")
+	sb.WriteString(strings.Repeat("=", 60))
+	sb.WriteString("\n")
+
+	for _, aviso := range avisos {
+		sb.WriteString(aviso.String())
 		sb.WriteString("\n")
-		sb.WriteString("--- ERROS DE VALIDAÇÃO CST ORIGEM (")
-		sb.WriteString(formatarNumero(len(resultado.ErrosCSTOrigem)))
-		sb.WriteString(") ---\n")
-		sb.WriteString(strings.Repeat("=", 60))
+	}
+
+	return sb.String()
+}
+
+// FormatarIgnorados formata a seção de problemas suprimidos pelo baseline (fora dos totais)
+func (f *Formatter) FormatarIgnorados(ignorados []domain.ErroValidacao) string {
+	var sb strings.Builder
+
+	if len(ignorados) == 0 {
+		return ""
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(strings.Repeat("=", 60))
+	sb.WriteString("\n")
+	sb.WriteString("--- IGNORADOS PELO BASELINE (")
+	sb.WriteString(formatarNumero(len(ignorados)))
+	sb.WriteString(") ---\n")
+	sb.WriteString(strings.Repeat("=", 60))
+	sb.WriteString("\n")
+
+	for _, ignorado := range ignorados {
+		sb.WriteString(ignorado.String())
 		sb.WriteString("\n")
+	}
 
-		for _, erro := range resultado.ErrosCSTOrigem {
-			sb.WriteString(erro.String())
-			sb.WriteString("\n")
-		}
+	return sb.String()
+}
+
+// FormatarComparacao formata o resultado da comparação entre duas versões da planilha
+func (f *Formatter) FormatarComparacao(c domain.Comparacao) string {
+	var sb strings.Builder
+
+	secoes := []struct {
+		titulo string
+		erros  []domain.ErroValidacao
+	}{
+		{"CORRIGIDOS", c.Corrigidos},
+		{"NOVOS", c.Novos},
+		{"PERSISTENTES", c.Persistentes},
 	}
 
-	// SEÇÃO 4: ERROS DE VALIDAÇÃO CSOSN
-	if len(resultado.ErrosCSOSN) > 0 {
+	for _, secao := range secoes {
 		sb.WriteString("\n")
 		sb.WriteString(strings.Repeat("=", 60))
 		sb.WriteString("\n")
-		sb.WriteString("--- ERROS DE VALIDAÇÃO CSOSN (")
-		sb.WriteString(formatarNumero(len(resultado.ErrosCSOSN)))
+		sb.WriteString("--- ")
+		sb.WriteString(secao.titulo)
+		sb.WriteString(" (")
+		sb.WriteString(formatarNumero(len(secao.erros)))
 		sb.WriteString(") ---\n")
 		sb.WriteString(strings.Repeat("=", 60))
 		sb.WriteString("\n")
 
-		for _, erro := range resultado.ErrosCSOSN {
+		for _, erro := range secao.erros {
 			sb.WriteString(erro.String())
 			sb.WriteString("\n")
 		}
 	}
 
-	// SEÇÃO 5: ERROS DE VALIDAÇÃO TIPO ITEM
-	if len(resultado.ErrosTipoItem) > 0 {
+	return sb.String()
+}
+
+// FormatarConferenciaNFe formata o cruzamento da planilha com os XMLs de NF-e
+func (f *Formatter) FormatarConferenciaNFe(c domain.ConferenciaNFe) string {
+	var sb strings.Builder
+
+	secao := func(titulo string, total int) {
 		sb.WriteString("\n")
 		sb.WriteString(strings.Repeat("=", 60))
 		sb.WriteString("\n")
-		sb.WriteString("--- ERROS DE VALIDAÇÃO TIPO ITEM (")
-		sb.WriteString(formatarNumero(len(resultado.ErrosTipoItem)))
+		sb.WriteString("--- ")
+		sb.WriteString(titulo)
+		sb.WriteString(" (")
+		sb.WriteString(formatarNumero(total))
 		sb.WriteString(") ---\n")
 		sb.WriteString(strings.Repeat("=", 60))
 		sb.WriteString("\n")
+	}
 
-		for _, erro := range resultado.ErrosTipoItem {
-			sb.WriteString(erro.String())
+	secao("DIVERGÊNCIAS ENTRE PLANILHA E NF-e", len(c.Divergencias))
+	for _, divergencia := range c.Divergencias {
+		sb.WriteString(divergencia.String())
+		sb.WriteString("\n")
+	}
+
+	if len(c.SemCorrespondencia) > 0 {
+		secao("ITENS DE NF-e SEM LINHA NA PLANILHA", len(c.SemCorrespondencia))
+		for _, item := range c.SemCorrespondencia {
+			sb.WriteString(fmt.Sprintf("%s, item %d: código '%s', GTIN '%s' — %s\n",
+				item.ArquivoXML, item.NumeroItem, item.Codigo, item.GTIN, item.Descricao))
+		}
+	}
+
+	if len(c.XMLsIgnorados) > 0 {
+		secao("XMLs IGNORADOS", len(c.XMLsIgnorados))
+		for _, ignorado := range c.XMLsIgnorados {
+			sb.WriteString(ignorado)
 			sb.WriteString("\n")
 		}
 	}
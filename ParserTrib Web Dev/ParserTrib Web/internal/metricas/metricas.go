@@ -0,0 +1,282 @@
+package metricas
+
+// Métricas do servidor expostas em GET /metrics no formato texto do Prometheus.
+// Implementação mínima (contadores e histogramas com labels) para não depender do client oficial.
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// bucketsDuracao são os limites (em segundos) usados nos histogramas de duração
+var bucketsDuracao = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// Métricas registradas pelo servidor
+var (
+	RequisicoesHTTP = NovoContador(
+		"parsertrib_http_requisicoes_total",
+		"Total de requisições HTTP atendidas",
+		"metodo", "rota", "status",
+	)
+	DuracaoHTTP = NovoHistograma(
+		"parsertrib_http_duracao_segundos",
+		"Latência das requisições HTTP em segundos",
+		bucketsDuracao,
+		"metodo", "rota",
+	)
+	ArquivosValidados = NovoContador(
+		"parsertrib_arquivos_validados_total",
+		"Total de planilhas validadas",
+	)
+	LinhasProcessadas = NovoContador(
+		"parsertrib_linhas_processadas_total",
+		"Total de linhas de dados processadas pelo validador",
+	)
+	DuracaoValidacao = NovoHistograma(
+		"parsertrib_validacao_duracao_segundos",
+		"Duração da validação de uma planilha em segundos",
+		bucketsDuracao,
+	)
+	ErrosValidacao = NovoContador(
+		"parsertrib_erros_validacao_total",
+		"Total de erros de validação encontrados, por tipo",
+		"tipo",
+	)
+	Operacoes = NovoContador(
+		"parsertrib_operacoes_total",
+		"Total de operações sobre planilhas que não entram nas métricas de validação, por operação",
+		"operacao",
+	)
+	DuracaoOperacao = NovoHistograma(
+		"parsertrib_operacao_duracao_segundos",
+		"Duração de uma operação sobre planilhas em segundos, por operação",
+		bucketsDuracao,
+		"operacao",
+	)
+)
+
+// registro guarda todas as métricas na ordem em que foram criadas
+var registro struct {
+	sync.Mutex
+	metricas []metrica
+}
+
+type metrica interface {
+	escrever(w io.Writer)
+}
+
+func registrar(m metrica) {
+	registro.Lock()
+	defer registro.Unlock()
+	registro.metricas = append(registro.metricas, m)
+}
+
+// Escrever serializa todas as métricas registradas no formato texto do Prometheus (versão 0.0.4)
+func Escrever(w io.Writer) {
+	registro.Lock()
+	metricas := append([]metrica(nil), registro.metricas...)
+	registro.Unlock()
+
+	for _, m := range metricas {
+		m.escrever(w)
+	}
+}
+
+// Contador é uma métrica monotônica crescente, opcionalmente particionada por labels
+type Contador struct {
+	nome    string
+	ajuda   string
+	labels  []string
+	mu      sync.Mutex
+	valores map[string]*serieContador
+}
+
+type serieContador struct {
+	valoresLabels []string
+	valor         float64
+}
+
+// NovoContador cria e registra um contador com os labels informados
+func NovoContador(nome, ajuda string, labels ...string) *Contador {
+	c := &Contador{
+		nome:    nome,
+		ajuda:   ajuda,
+		labels:  labels,
+		valores: make(map[string]*serieContador),
+	}
+	registrar(c)
+	return c
+}
+
+// Inc incrementa o contador em 1 para os valores de label informados
+func (c *Contador) Inc(valoresLabels ...string) {
+	c.Add(1, valoresLabels...)
+}
+
+// Add soma delta (não negativo) ao contador para os valores de label informados
+func (c *Contador) Add(delta float64, valoresLabels ...string) {
+	if delta < 0 {
+		return
+	}
+	chave := chaveLabels(c.labels, valoresLabels)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	serie, ok := c.valores[chave]
+	if !ok {
+		serie = &serieContador{valoresLabels: append([]string(nil), valoresLabels...)}
+		c.valores[chave] = serie
+	}
+	serie.valor += delta
+}
+
+func (c *Contador) escrever(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n", c.nome, c.ajuda)
+	fmt.Fprintf(w, "# TYPE %s counter\n", c.nome)
+	if len(c.labels) == 0 && len(c.valores) == 0 {
+		fmt.Fprintf(w, "%s 0\n", c.nome)
+		return
+	}
+	for _, chave := range chavesOrdenadas(c.valores) {
+		serie := c.valores[chave]
+		fmt.Fprintf(w, "%s%s %s\n", c.nome, formatarLabels(c.labels, serie.valoresLabels), formatarValor(serie.valor))
+	}
+}
+
+// Histograma acumula observações em buckets cumulativos, opcionalmente particionado por labels
+type Histograma struct {
+	nome    string
+	ajuda   string
+	buckets []float64
+	labels  []string
+	mu      sync.Mutex
+	series  map[string]*serieHistograma
+}
+
+type serieHistograma struct {
+	valoresLabels []string
+	contagens     []uint64
+	soma          float64
+	total         uint64
+}
+
+// NovoHistograma cria e registra um histograma com os buckets (limites superiores) informados
+func NovoHistograma(nome, ajuda string, buckets []float64, labels ...string) *Histograma {
+	h := &Histograma{
+		nome:    nome,
+		ajuda:   ajuda,
+		buckets: append([]float64(nil), buckets...),
+		labels:  labels,
+		series:  make(map[string]*serieHistograma),
+	}
+	sort.Float64s(h.buckets)
+	registrar(h)
+	return h
+}
+
+// Observar registra um valor no histograma para os valores de label informados
+func (h *Histograma) Observar(valor float64, valoresLabels ...string) {
+	chave := chaveLabels(h.labels, valoresLabels)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	serie, ok := h.series[chave]
+	if !ok {
+		serie = &serieHistograma{
+			valoresLabels: append([]string(nil), valoresLabels...),
+			contagens:     make([]uint64, len(h.buckets)),
+		}
+		h.series[chave] = serie
+	}
+	for i, limite := range h.buckets {
+		if valor <= limite {
+			serie.contagens[i]++
+		}
+	}
+	serie.soma += valor
+	serie.total++
+}
+
+func (h *Histograma) escrever(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n", h.nome, h.ajuda)
+	fmt.Fprintf(w, "# TYPE %s histogram\n", h.nome)
+	labelsBucket := append(append([]string(nil), h.labels...), "le")
+	for _, chave := range chavesOrdenadas(h.series) {
+		serie := h.series[chave]
+		valoresBucket := append(append([]string(nil), serie.valoresLabels...), "")
+		for i, limite := range h.buckets {
+			valoresBucket[len(valoresBucket)-1] = formatarValor(limite)
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.nome, formatarLabels(labelsBucket, valoresBucket), serie.contagens[i])
+		}
+		valoresBucket[len(valoresBucket)-1] = "+Inf"
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.nome, formatarLabels(labelsBucket, valoresBucket), serie.total)
+
+		labels := formatarLabels(h.labels, serie.valoresLabels)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.nome, labels, formatarValor(serie.soma))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.nome, labels, serie.total)
+	}
+}
+
+// chaveLabels monta a chave interna da série; valores faltantes viram string vazia
+func chaveLabels(labels, valores []string) string {
+	partes := make([]string, len(labels))
+	for i := range labels {
+		if i < len(valores) {
+			partes[i] = valores[i]
+		}
+	}
+	return strings.Join(partes, "\xff")
+}
+
+func chavesOrdenadas[T any](m map[string]T) []string {
+	chaves := make([]string, 0, len(m))
+	for k := range m {
+		chaves = append(chaves, k)
+	}
+	sort.Strings(chaves)
+	return chaves
+}
+
+// formatarLabels gera o trecho {a="1",b="2"} com escape conforme o formato texto
+func formatarLabels(labels, valores []string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	escapador := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+	var sb strings.Builder
+	sb.WriteString("{")
+	for i, label := range labels {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		valor := ""
+		if i < len(valores) {
+			valor = valores[i]
+		}
+		sb.WriteString(label)
+		sb.WriteString(`="`)
+		sb.WriteString(escapador.Replace(valor))
+		sb.WriteString(`"`)
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
+
+func formatarValor(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,98 @@
+package metricas
+
+import (
+	"strings"
+	"testing"
+)
+
+// textoMetrica serializa só a métrica informada
+func textoMetrica(m metrica) string {
+	var sb strings.Builder
+	m.escrever(&sb)
+	return sb.String()
+}
+
+func TestContador(t *testing.T) {
+	casos := []struct {
+		nome     string
+		labels   []string
+		incluir  func(c *Contador)
+		esperado string
+	}{
+		{
+			nome:     "sem labels e sem observações sai zerado",
+			incluir:  func(c *Contador) {},
+			esperado: "# HELP teste_total Ajuda\n# TYPE teste_total counter\nteste_total 0\n",
+		},
+		{
+			nome:   "séries ordenadas pelos valores dos labels",
+			labels: []string{"metodo", "status"},
+			incluir: func(c *Contador) {
+				c.Inc("POST", "200")
+				c.Inc("GET", "200")
+				c.Add(2.5, "GET", "200")
+				c.Add(-1, "GET", "200") // ignorado: contador não diminui
+			},
+			esperado: "# HELP teste_total Ajuda\n# TYPE teste_total counter\n" +
+				"teste_total{metodo=\"GET\",status=\"200\"} 3.5\n" +
+				"teste_total{metodo=\"POST\",status=\"200\"} 1\n",
+		},
+		{
+			nome:     "escape de aspas, barra e quebra de linha",
+			labels:   []string{"rota"},
+			incluir:  func(c *Contador) { c.Inc("a\"b\\c\nd") },
+			esperado: "# HELP teste_total Ajuda\n# TYPE teste_total counter\nteste_total{rota=\"a\\\"b\\\\c\\nd\"} 1\n",
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			c := NovoContador("teste_total", "Ajuda", caso.labels...)
+			caso.incluir(c)
+			if texto := textoMetrica(c); texto != caso.esperado {
+				t.Errorf("saída =\n%s\nesperado\n%s", texto, caso.esperado)
+			}
+		})
+	}
+}
+
+func TestHistograma(t *testing.T) {
+	h := NovoHistograma("teste_segundos", "Ajuda", []float64{1, 0.1, 0.5}, "rota")
+	for _, valor := range []float64{0.05, 0.3, 0.3, 2} {
+		h.Observar(valor, "/api/validar")
+	}
+
+	esperado := "# HELP teste_segundos Ajuda\n# TYPE teste_segundos histogram\n" +
+		"teste_segundos_bucket{rota=\"/api/validar\",le=\"0.1\"} 1\n" +
+		"teste_segundos_bucket{rota=\"/api/validar\",le=\"0.5\"} 3\n" +
+		"teste_segundos_bucket{rota=\"/api/validar\",le=\"1\"} 3\n" +
+		"teste_segundos_bucket{rota=\"/api/validar\",le=\"+Inf\"} 4\n" +
+		"teste_segundos_sum{rota=\"/api/validar\"} 2.65\n" +
+		"teste_segundos_count{rota=\"/api/validar\"} 4\n"
+	if texto := textoMetrica(h); texto != esperado {
+		t.Errorf("saída =\n%s\nesperado\n%s", texto, esperado)
+	}
+}
+
+func TestEscrever(t *testing.T) {
+	var sb strings.Builder
+	Escrever(&sb)
+	texto := sb.String()
+
+	for _, trecho := range []string{
+		"# TYPE parsertrib_http_requisicoes_total counter\n",
+		"# TYPE parsertrib_http_duracao_segundos histogram\n",
+		"# TYPE parsertrib_arquivos_validados_total counter\nparsertrib_arquivos_validados_total 0\n",
+		"# TYPE parsertrib_validacao_duracao_segundos histogram\n",
+		"# TYPE parsertrib_operacoes_total counter\n",
+		"# TYPE parsertrib_operacao_duracao_segundos histogram\n",
+	} {
+		if !strings.Contains(texto, trecho) {
+			t.Errorf("saída de Escrever sem o trecho %q", trecho)
+		}
+	}
+	if strings.Index(texto, "parsertrib_http_requisicoes_total") > strings.Index(texto, "parsertrib_erros_validacao_total") ||
+		strings.Index(texto, "parsertrib_erros_validacao_total") > strings.Index(texto, "parsertrib_operacoes_total") {
+		t.Error("métricas fora da ordem de registro")
+	}
+}
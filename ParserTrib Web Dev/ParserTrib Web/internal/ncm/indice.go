@@ -0,0 +1,198 @@
+package ncm
+
+// Índice offline de similaridade de texto (TF-IDF + cosseno) sobre as descrições da tabela NCM,
+// usado para sugerir códigos a partir da descrição do produto
+
+import (
+	"ParserTrib/internal/domain"
+	"ParserTrib/internal/texto"
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// regexCodigo aceita o NCM com ou sem pontuação ("9608.10.00" ou "96081000")
+var regexCodigo = regexp.MustCompile(`^\d{4}\.?\d{2}\.?\d{2}$`)
+
+// palavrasIgnoradas não ajudam a distinguir produtos
+var palavrasIgnoradas = map[string]bool{
+	"a": true, "o": true, "as": true, "os": true, "e": true, "ou": true,
+	"de": true, "da": true, "do": true, "das": true, "dos": true,
+	"em": true, "na": true, "no": true, "nas": true, "nos": true,
+	"para": true, "por": true, "com": true, "sem": true, "outros": true, "outras": true,
+}
+
+// Entrada é uma linha da tabela NCM
+type Entrada struct {
+	Codigo    string
+	Descricao string
+}
+
+// ocorrencia é o peso de um termo em uma entrada da tabela
+type ocorrencia struct {
+	entrada int
+	peso    float64
+}
+
+// Indice guarda os vetores TF-IDF das descrições em forma de índice invertido
+type Indice struct {
+	entradas []Entrada
+	idf      map[string]float64
+	termos   map[string][]ocorrencia
+	normas   []float64
+}
+
+// CarregarCSV lê a tabela NCM de um CSV com as colunas código e descrição (separador ";" ou ",").
+// Linhas de cabeçalho e códigos que não têm 8 dígitos (capítulos, posições) são ignorados.
+func CarregarCSV(caminho string) (*Indice, error) {
+	f, err := os.Open(caminho)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir tabela NCM: %w", err)
+	}
+	defer f.Close()
+
+	leitor := bufio.NewReader(f)
+	primeiraLinha, _ := leitor.Peek(4096)
+
+	r := csv.NewReader(leitor)
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	if strings.Count(string(primeiraLinha), ";") > 0 {
+		r.Comma = ';'
+	}
+
+	var entradas []Entrada
+	for {
+		registro, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler tabela NCM: %w", err)
+		}
+		if len(registro) < 2 {
+			continue
+		}
+
+		codigo := strings.TrimSpace(strings.TrimPrefix(registro[0], "\ufeff"))
+		if !regexCodigo.MatchString(codigo) {
+			continue
+		}
+		entradas = append(entradas, Entrada{
+			Codigo:    strings.ReplaceAll(codigo, ".", ""),
+			Descricao: strings.TrimSpace(registro[1]),
+		})
+	}
+
+	if len(entradas) == 0 {
+		return nil, fmt.Errorf("tabela NCM '%s' não tem nenhum código de 8 dígitos", caminho)
+	}
+	return NovoIndice(entradas), nil
+}
+
+// NovoIndice monta o índice TF-IDF a partir das entradas da tabela
+func NovoIndice(entradas []Entrada) *Indice {
+	indice := &Indice{
+		entradas: entradas,
+		idf:      make(map[string]float64),
+		termos:   make(map[string][]ocorrencia),
+		normas:   make([]float64, len(entradas)),
+	}
+
+	frequencias := make([]map[string]int, len(entradas))
+	documentosPorTermo := make(map[string]int)
+	for i, e := range entradas {
+		frequencias[i] = contarTermos(e.Descricao)
+		for termo := range frequencias[i] {
+			documentosPorTermo[termo]++
+		}
+	}
+
+	total := float64(len(entradas))
+	for termo, df := range documentosPorTermo {
+		indice.idf[termo] = math.Log(1 + total/float64(df))
+	}
+
+	for i, tf := range frequencias {
+		for termo, n := range tf {
+			peso := float64(n) * indice.idf[termo]
+			indice.termos[termo] = append(indice.termos[termo], ocorrencia{entrada: i, peso: peso})
+			indice.normas[i] += peso * peso
+		}
+		indice.normas[i] = math.Sqrt(indice.normas[i])
+	}
+
+	return indice
+}
+
+// Total retorna a quantidade de códigos carregados
+func (ix *Indice) Total() int {
+	return len(ix.entradas)
+}
+
+// Sugerir devolve os n códigos cuja descrição mais se parece com a informada (similaridade de cosseno,
+// de 0 a 1). Termos que não existem na tabela são ignorados; sem termos em comum, não há sugestão.
+func (ix *Indice) Sugerir(descricao string, n int) []domain.SugestaoNCM {
+	consulta := contarTermos(descricao)
+
+	pontuacoes := make(map[int]float64)
+	var normaConsulta float64
+	for termo, tf := range consulta {
+		idf, ok := ix.idf[termo]
+		if !ok {
+			continue
+		}
+		peso := float64(tf) * idf
+		normaConsulta += peso * peso
+		for _, oc := range ix.termos[termo] {
+			pontuacoes[oc.entrada] += peso * oc.peso
+		}
+	}
+	if len(pontuacoes) == 0 {
+		return nil
+	}
+	normaConsulta = math.Sqrt(normaConsulta)
+
+	candidatos := make([]domain.SugestaoNCM, 0, len(pontuacoes))
+	for i, produto := range pontuacoes {
+		candidatos = append(candidatos, domain.SugestaoNCM{
+			NCM:       ix.entradas[i].Codigo,
+			Descricao: ix.entradas[i].Descricao,
+			Pontuacao: math.Round(produto/(normaConsulta*ix.normas[i])*1000) / 1000,
+		})
+	}
+
+	sort.Slice(candidatos, func(i, j int) bool {
+		if candidatos[i].Pontuacao != candidatos[j].Pontuacao {
+			return candidatos[i].Pontuacao > candidatos[j].Pontuacao
+		}
+		return candidatos[i].NCM < candidatos[j].NCM
+	})
+
+	if len(candidatos) > n {
+		candidatos = candidatos[:n]
+	}
+	return candidatos
+}
+
+// contarTermos (privada para uso interno) conta os termos normalizados relevantes de um texto.
+// O plural simples é reduzido ao singular ("canetas" → "caneta") para casar com a tabela.
+func contarTermos(s string) map[string]int {
+	termos := make(map[string]int)
+	for _, token := range texto.Tokens(s) {
+		if len(token) < 2 || palavrasIgnoradas[token] {
+			continue
+		}
+		if len(token) > 3 && strings.HasSuffix(token, "s") {
+			token = strings.TrimSuffix(token, "s")
+		}
+		termos[token]++
+	}
+	return termos
+}
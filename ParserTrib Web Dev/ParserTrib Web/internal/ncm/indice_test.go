@@ -0,0 +1,114 @@
+package ncm
+
+import (
+	"ParserTrib/internal/domain"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCarregarCSV(t *testing.T) {
+	casos := []struct {
+		nome     string
+		conteudo string
+		esperado []Entrada
+	}{
+		{
+			nome:     "separador ponto e vírgula com BOM e pontuação",
+			conteudo: "\ufeff9608.10.00;Canetas esferográficas\n96;Capítulo 96\n9608;Canetas e lapiseiras\n9609.10.00; Lápis \n",
+			esperado: []Entrada{{Codigo: "96081000", Descricao: "Canetas esferográficas"}, {Codigo: "96091000", Descricao: "Lápis"}},
+		},
+		{
+			nome:     "separador vírgula com cabeçalho e aspas",
+			conteudo: "codigo,descricao\n96081000,\"Canetas, esferográficas\"\n960810,Incompleto\n48201000,Cadernos\n",
+			esperado: []Entrada{{Codigo: "96081000", Descricao: "Canetas, esferográficas"}, {Codigo: "48201000", Descricao: "Cadernos"}},
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			caminho := filepath.Join(t.TempDir(), "ncm.csv")
+			if err := os.WriteFile(caminho, []byte(caso.conteudo), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			indice, err := CarregarCSV(caminho)
+			if err != nil {
+				t.Fatalf("CarregarCSV: %v", err)
+			}
+			if !reflect.DeepEqual(indice.entradas, caso.esperado) {
+				t.Errorf("entradas = %+v, esperado %+v", indice.entradas, caso.esperado)
+			}
+		})
+	}
+
+	t.Run("sem códigos de 8 dígitos", func(t *testing.T) {
+		caminho := filepath.Join(t.TempDir(), "ncm.csv")
+		if err := os.WriteFile(caminho, []byte("96;Capítulo\n9608;Posição\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := CarregarCSV(caminho); err == nil {
+			t.Error("esperado erro para tabela sem códigos válidos")
+		}
+	})
+
+	t.Run("arquivo inexistente", func(t *testing.T) {
+		if _, err := CarregarCSV(filepath.Join(t.TempDir(), "nao_existe.csv")); err == nil {
+			t.Error("esperado erro para arquivo inexistente")
+		}
+	})
+}
+
+func TestSugerir(t *testing.T) {
+	indice := NovoIndice([]Entrada{
+		{Codigo: "96082000", Descricao: "Canetas e marcadores, com ponta de feltro"},
+		{Codigo: "96081000", Descricao: "Canetas esferográficas"},
+		{Codigo: "96091000", Descricao: "Lápis"},
+		{Codigo: "48201000", Descricao: "Cadernos"},
+		{Codigo: "96089900", Descricao: "Outras canetas tinteiro"},
+		{Codigo: "96089100", Descricao: "Outras canetas tinteiro"},
+	})
+	if indice.Total() != 6 {
+		t.Fatalf("Total = %d, esperado 6", indice.Total())
+	}
+
+	codigos := func(sugestoes []domain.SugestaoNCM) []string {
+		var resultado []string
+		for _, s := range sugestoes {
+			resultado = append(resultado, s.NCM)
+		}
+		return resultado
+	}
+
+	t.Run("descrição idêntica no singular fica em primeiro com pontuação 1", func(t *testing.T) {
+		sugestoes := indice.Sugerir("CANETA ESFEROGRAFICA AZUL", 3)
+		if len(sugestoes) != 3 || sugestoes[0].NCM != "96081000" || sugestoes[0].Pontuacao != 1 {
+			t.Fatalf("sugestões = %+v, esperado 96081000 com pontuação 1 e 3 candidatos", sugestoes)
+		}
+		if sugestoes[1].Pontuacao > sugestoes[0].Pontuacao || sugestoes[2].Pontuacao > sugestoes[1].Pontuacao {
+			t.Errorf("sugestões fora de ordem: %+v", sugestoes)
+		}
+	})
+
+	t.Run("empate desfeito pelo código", func(t *testing.T) {
+		obtidos := codigos(indice.Sugerir("caneta tinteiro", 2))
+		if esperados := []string{"96089100", "96089900"}; !reflect.DeepEqual(obtidos, esperados) {
+			t.Errorf("sugestões = %q, esperado %q", obtidos, esperados)
+		}
+	})
+
+	t.Run("sem termos em comum", func(t *testing.T) {
+		if sugestoes := indice.Sugerir("borracha escolar", 3); sugestoes != nil {
+			t.Errorf("sugestões = %+v, esperado nenhuma", sugestoes)
+		}
+	})
+}
+
+func TestContarTermos(t *testing.T) {
+	obtidos := contarTermos("Canetas de Gás para o Escritório, canetas e lápis")
+	esperados := map[string]int{"caneta": 2, "gas": 1, "escritorio": 1, "lapi": 1}
+	if !maps.Equal(obtidos, esperados) {
+		t.Errorf("contarTermos = %v, esperado %v", obtidos, esperados)
+	}
+}
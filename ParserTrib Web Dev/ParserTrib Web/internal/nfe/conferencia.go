@@ -0,0 +1,147 @@
+package nfe
+
+import (
+	"ParserTrib/internal/domain"
+	"ParserTrib/internal/excel"
+	"ParserTrib/internal/texto"
+	"strings"
+	"unicode"
+)
+
+// similaridadeMinimaDescricao abaixo da qual as descrições da planilha e da nota são consideradas
+// de produtos diferentes (as notas costumam abreviar ou reordenar a descrição)
+const similaridadeMinimaDescricao = 0.5
+
+// campoConferido liga uma coluna da planilha ao valor correspondente no item da nota
+type campoConferido struct {
+	coluna     string
+	valorNFe   func(domain.ItemNFe) string
+	normalizar func(string) string
+	iguais     func(planilha, nfe string) bool
+}
+
+var camposConferidos = []campoConferido{
+	{excel.ColunaNCM, func(i domain.ItemNFe) string { return i.NCM }, somenteDigitos, nil},
+	{excel.ColunaCEST, func(i domain.ItemNFe) string { return i.CEST }, somenteDigitos, nil},
+	// A coluna CSOSN recebe o CSOSN (Simples Nacional) ou, na falta dele, o CST do regime normal
+	{excel.ColunaCSOSN, func(i domain.ItemNFe) string {
+		if i.CSOSN != "" {
+			return i.CSOSN
+		}
+		return i.CST
+	}, strings.TrimSpace, nil},
+	{excel.ColunaCSTOrigem, func(i domain.ItemNFe) string { return i.Origem }, strings.TrimSpace, nil},
+	{excel.ColunaDescricao, func(i domain.ItemNFe) string { return i.Descricao }, strings.TrimSpace, descricoesSemelhantes},
+}
+
+// Conferir casa cada item das notas com uma linha da planilha — pelo código do produto (cProd) e,
+// na falta dele, pelo GTIN — e aponta as divergências nos campos fiscais e na descrição.
+// Colunas ausentes na planilha não são conferidas.
+func Conferir(nomeArquivo string, cabecalhos []string, rows [][]string, lote Lote) domain.ConferenciaNFe {
+	conferencia := domain.ConferenciaNFe{
+		NomeArquivo:        nomeArquivo,
+		TotalXMLs:          lote.TotalXMLs,
+		TotalItens:         len(lote.Itens),
+		Divergencias:       []domain.DivergenciaNFe{},
+		SemCorrespondencia: []domain.ItemNFe{},
+		XMLsIgnorados:      append([]string{}, lote.Ignorados...),
+	}
+
+	indices := make(map[string]int, len(cabecalhos))
+	for i, cab := range cabecalhos {
+		indices[cab] = i
+	}
+	valor := func(row []string, coluna string) string {
+		if indice, ok := indices[coluna]; ok && indice < len(row) {
+			return strings.TrimSpace(row[indice])
+		}
+		return ""
+	}
+
+	// Índices código → linha e GTIN → linha (a primeira ocorrência prevalece)
+	porCodigo := make(map[string]int)
+	porGTIN := make(map[string]int)
+	for i := 1; i < len(rows); i++ {
+		if codigo := valor(rows[i], excel.ColunaCodigo); codigo != "" {
+			if _, existe := porCodigo[codigo]; !existe {
+				porCodigo[codigo] = i
+			}
+		}
+		if gtin := somenteDigitos(valor(rows[i], excel.ColunaGTIN)); gtin != "" {
+			if _, existe := porGTIN[gtin]; !existe {
+				porGTIN[gtin] = i
+			}
+		}
+	}
+
+	for _, item := range lote.Itens {
+		indice, casadoPor := -1, ""
+		if i, ok := porCodigo[item.Codigo]; ok && item.Codigo != "" {
+			indice, casadoPor = i, domain.CasadoPorCodigo
+		} else if i, ok := porGTIN[somenteDigitos(item.GTIN)]; ok && item.GTIN != "" {
+			indice, casadoPor = i, domain.CasadoPorGTIN
+		}
+		if indice < 0 {
+			conferencia.SemCorrespondencia = append(conferencia.SemCorrespondencia, item)
+			continue
+		}
+
+		conferencia.TotalConferidos++
+		row := rows[indice]
+		for _, campo := range camposConferidos {
+			if _, existe := indices[campo.coluna]; !existe {
+				continue
+			}
+			naPlanilha := valor(row, campo.coluna)
+			naNota := campo.valorNFe(item)
+
+			iguais := campo.iguais
+			if iguais == nil {
+				iguais = func(a, b string) bool { return campo.normalizar(a) == campo.normalizar(b) }
+			}
+			if iguais(naPlanilha, naNota) {
+				continue
+			}
+
+			conferencia.Divergencias = append(conferencia.Divergencias, domain.DivergenciaNFe{
+				Linha:         indice + 1,
+				Chave:         valor(row, excel.ColunaCodigo),
+				Campo:         campo.coluna,
+				ValorPlanilha: naPlanilha,
+				ValorNFe:      naNota,
+				CasadoPor:     casadoPor,
+				Item:          item,
+			})
+		}
+	}
+
+	conferencia.TotalDivergencias = len(conferencia.Divergencias)
+	return conferencia
+}
+
+// descricoesSemelhantes compara os termos das descrições, sem acentos, caixa e unidade de venda
+func descricoesSemelhantes(planilha, nfe string) bool {
+	a, b := termos(planilha), termos(nfe)
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return texto.Jaccard(a, b) >= similaridadeMinimaDescricao
+}
+
+func termos(descricao string) map[string]bool {
+	conjunto := make(map[string]bool)
+	for _, termo := range strings.Fields(texto.NormalizarDescricao(descricao)) {
+		conjunto[termo] = true
+	}
+	return conjunto
+}
+
+// somenteDigitos remove pontos, traços e espaços de códigos como NCM ("9608.10.00") e CEST
+func somenteDigitos(valor string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, valor)
+}
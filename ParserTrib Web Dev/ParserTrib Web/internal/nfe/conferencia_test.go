@@ -0,0 +1,83 @@
+package nfe
+
+import (
+	"ParserTrib/internal/domain"
+	"ParserTrib/internal/excel"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestConferir(t *testing.T) {
+	cabecalhos := []string{excel.ColunaCodigo, excel.ColunaDescricao, excel.ColunaGTIN, excel.ColunaNCM, excel.ColunaCSOSN, excel.ColunaCSTOrigem}
+	rows := [][]string{
+		cabecalhos,
+		{"P1", "Caneta esferográfica azul", "7891234567895", "9608.10.00", "102", "0"},
+		{"P2", "Lápis preto", "7890000000017", "96091000", "00", "0"},
+		{"P3", "Borracha branca", "", "40169200", "102", "0"},
+	}
+
+	casos := []struct {
+		nome          string
+		item          domain.ItemNFe
+		divergencias  []string
+		semLinha      bool
+		totalConferia int
+	}{
+		{
+			nome:          "casado pelo código, sem divergência",
+			item:          domain.ItemNFe{Codigo: "P1", Descricao: "CANETA ESFEROGRAFICA AZUL", NCM: "96081000", CSOSN: "102", Origem: "0"},
+			totalConferia: 1,
+		},
+		{
+			nome:          "casado pelo GTIN usa o CST na falta do CSOSN",
+			item:          domain.ItemNFe{Codigo: "X9", GTIN: "7890000000017", Descricao: "Lápis preto", NCM: "96091000", CST: "00", Origem: "0"},
+			totalConferia: 1,
+		},
+		{
+			nome:          "campos fiscais e descrição divergentes",
+			item:          domain.ItemNFe{Codigo: "P3", Descricao: "Apontador metálico", NCM: "40169200", CSOSN: "500", Origem: "1"},
+			divergencias:  []string{"4 P3 CSOSN CODIGO", "4 P3 CST Origem CODIGO", "4 P3 Descrição CODIGO"},
+			totalConferia: 1,
+		},
+		{
+			nome:     "item sem linha correspondente",
+			item:     domain.ItemNFe{Codigo: "X9", GTIN: "7899999999999"},
+			semLinha: true,
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			conferencia := Conferir("produtos.xlsx", cabecalhos, rows, Lote{Itens: []domain.ItemNFe{caso.item}, TotalXMLs: 1})
+
+			var resumo []string
+			for _, d := range conferencia.Divergencias {
+				resumo = append(resumo, fmt.Sprintf("%d %s %s %s", d.Linha, d.Chave, d.Campo, d.CasadoPor))
+			}
+			if !slices.Equal(resumo, caso.divergencias) {
+				t.Errorf("divergências = %q, esperado %q", resumo, caso.divergencias)
+			}
+			if conferencia.TotalDivergencias != len(caso.divergencias) {
+				t.Errorf("TotalDivergencias = %d, esperado %d", conferencia.TotalDivergencias, len(caso.divergencias))
+			}
+			if conferencia.TotalConferidos != caso.totalConferia {
+				t.Errorf("TotalConferidos = %d, esperado %d", conferencia.TotalConferidos, caso.totalConferia)
+			}
+			if semLinha := len(conferencia.SemCorrespondencia) == 1; semLinha != caso.semLinha {
+				t.Errorf("item sem correspondência = %v, esperado %v", semLinha, caso.semLinha)
+			}
+		})
+	}
+}
+
+func TestConferirColunaAusente(t *testing.T) {
+	cabecalhos := []string{excel.ColunaCodigo, excel.ColunaNCM}
+	rows := [][]string{cabecalhos, {"P1", "96081000"}}
+	item := domain.ItemNFe{Codigo: "P1", Descricao: "Qualquer coisa", NCM: "96081000", CSOSN: "500", Origem: "2"}
+
+	conferencia := Conferir("produtos.xlsx", cabecalhos, rows, Lote{Itens: []domain.ItemNFe{item}})
+	if len(conferencia.Divergencias) != 0 {
+		t.Errorf("divergências = %v, esperado nenhuma (colunas ausentes não são conferidas)", conferencia.Divergencias)
+	}
+}
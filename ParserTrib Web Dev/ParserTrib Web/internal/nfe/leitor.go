@@ -0,0 +1,225 @@
+package nfe
+
+// Leitura dos itens (grupos det/prod e det/imposto/ICMS) de XMLs de NF-e, soltos, em pasta ou em .zip
+
+import (
+	"ParserTrib/internal/domain"
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Lote reúne os itens lidos de um conjunto de XMLs
+type Lote struct {
+	Itens     []domain.ItemNFe
+	TotalXMLs int
+	Ignorados []string // XMLs que não são NF-e ou não puderam ser lidos
+}
+
+// infNFe é o subconjunto do grupo infNFe usado na conferência (vale para nfeProc e para NFe sem protocolo)
+type infNFe struct {
+	ID  string `xml:"Id,attr"`
+	Det []struct {
+		NumeroItem int `xml:"nItem,attr"`
+		Prod       struct {
+			Codigo    string `xml:"cProd"`
+			GTIN      string `xml:"cEAN"`
+			Descricao string `xml:"xProd"`
+			NCM       string `xml:"NCM"`
+			CEST      string `xml:"CEST"`
+		} `xml:"prod"`
+		Imposto struct {
+			ICMS struct {
+				// ICMS00, ICMS20, ..., ICMSSN101, ICMSSN102, ... — só um por item
+				Grupos []struct {
+					Origem string `xml:"orig"`
+					CST    string `xml:"CST"`
+					CSOSN  string `xml:"CSOSN"`
+				} `xml:",any"`
+			} `xml:"ICMS"`
+		} `xml:"imposto"`
+	} `xml:"det"`
+}
+
+// Limites da leitura de .zip: o tamanho declarado no pacote é conferido antes de descompactar e a
+// leitura é cortada no limite, para que um .zip pequeno não se expanda até esgotar a memória
+const (
+	maximoArquivosZIP     = 10000
+	tamanhoMaximoXML      = 10 << 20  // 10MB por XML descompactado
+	tamanhoMaximoConteudo = 512 << 20 // 512MB descompactados por .zip
+)
+
+// errSemNFe indica um XML válido que não contém o grupo infNFe (ex.: evento, CT-e)
+var errSemNFe = errors.New("XML não contém o grupo infNFe")
+
+// Carregar lê os XMLs dos caminhos informados: arquivos .xml, arquivos .zip ou pastas (percorridas
+// recursivamente, incluindo os .zip encontrados). Arquivos que não são NF-e vão para Ignorados.
+func Carregar(caminhos ...string) (Lote, error) {
+	var lote Lote
+
+	for _, caminho := range caminhos {
+		info, err := os.Stat(caminho)
+		if err != nil {
+			return lote, fmt.Errorf("erro ao acessar '%s': %w", caminho, err)
+		}
+		if !info.IsDir() {
+			if err := lote.carregarArquivo(caminho); err != nil {
+				return lote, err
+			}
+			continue
+		}
+
+		err = filepath.WalkDir(caminho, func(atual string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			return lote.carregarArquivo(atual)
+		})
+		if err != nil {
+			return lote, fmt.Errorf("erro ao percorrer a pasta '%s': %w", caminho, err)
+		}
+	}
+
+	return lote, nil
+}
+
+// carregarArquivo trata um .xml ou um .zip; outras extensões são desconsideradas
+func (l *Lote) carregarArquivo(caminho string) error {
+	switch strings.ToLower(filepath.Ext(caminho)) {
+	case ".xml":
+		dados, err := os.ReadFile(caminho)
+		if err != nil {
+			return fmt.Errorf("erro ao ler '%s': %w", caminho, err)
+		}
+		l.adicionar(filepath.Base(caminho), dados)
+	case ".zip":
+		return l.carregarZIP(caminho)
+	}
+	return nil
+}
+
+// carregarZIP lê os .xml contidos no arquivo compactado (em qualquer subpasta). Pacotes acima dos
+// limites de quantidade ou de tamanho descompactado são recusados por inteiro.
+func (l *Lote) carregarZIP(caminho string) error {
+	pacote, err := zip.OpenReader(caminho)
+	if err != nil {
+		return fmt.Errorf("erro ao abrir '%s': %w", caminho, err)
+	}
+	defer pacote.Close()
+
+	if len(pacote.File) > maximoArquivosZIP {
+		return fmt.Errorf("'%s' tem %d arquivos (máximo: %d)", caminho, len(pacote.File), maximoArquivosZIP)
+	}
+
+	var total uint64
+	for _, arquivo := range pacote.File {
+		if arquivo.FileInfo().IsDir() || !strings.EqualFold(filepath.Ext(arquivo.Name), ".xml") {
+			continue
+		}
+		if arquivo.UncompressedSize64 > tamanhoMaximoXML {
+			return fmt.Errorf("'%s' em '%s' excede %d MB descompactado", arquivo.Name, caminho, tamanhoMaximoXML>>20)
+		}
+		if total += arquivo.UncompressedSize64; total > tamanhoMaximoConteudo {
+			return fmt.Errorf("'%s' excede %d MB descompactados", caminho, tamanhoMaximoConteudo>>20)
+		}
+
+		dados, err := lerArquivoZIP(arquivo)
+		if err != nil {
+			return fmt.Errorf("erro ao ler '%s' em '%s': %w", arquivo.Name, caminho, err)
+		}
+		l.adicionar(filepath.Base(arquivo.Name), dados)
+	}
+	return nil
+}
+
+// lerArquivoZIP descompacta um arquivo do pacote sem passar de tamanhoMaximoXML, mesmo que o tamanho
+// declarado no cabeçalho do .zip seja falso
+func lerArquivoZIP(arquivo *zip.File) ([]byte, error) {
+	conteudo, err := arquivo.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer conteudo.Close()
+
+	dados, err := io.ReadAll(io.LimitReader(conteudo, tamanhoMaximoXML+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(dados) > tamanhoMaximoXML {
+		return nil, fmt.Errorf("excede %d MB descompactado", tamanhoMaximoXML>>20)
+	}
+	return dados, nil
+}
+
+// adicionar interpreta um XML e acumula seus itens; falhas de leitura não interrompem o lote
+func (l *Lote) adicionar(nome string, dados []byte) {
+	l.TotalXMLs++
+	itens, err := LerXML(nome, dados)
+	if err != nil {
+		l.Ignorados = append(l.Ignorados, fmt.Sprintf("%s: %v", nome, err))
+		return
+	}
+	l.Itens = append(l.Itens, itens...)
+}
+
+// LerXML extrai os itens de um XML de NF-e (com ou sem o envelope nfeProc)
+func LerXML(nome string, dados []byte) ([]domain.ItemNFe, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(dados))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil, errSemNFe
+		}
+		if err != nil {
+			return nil, fmt.Errorf("erro ao interpretar XML: %w", err)
+		}
+
+		inicio, ok := token.(xml.StartElement)
+		if !ok || inicio.Name.Local != "infNFe" {
+			continue
+		}
+
+		var nota infNFe
+		if err := decoder.DecodeElement(&nota, &inicio); err != nil {
+			return nil, fmt.Errorf("erro ao interpretar infNFe: %w", err)
+		}
+		return nota.itens(nome), nil
+	}
+}
+
+// itens converte os grupos det da nota
+func (n infNFe) itens(nome string) []domain.ItemNFe {
+	chave := strings.TrimPrefix(n.ID, "NFe")
+	itens := make([]domain.ItemNFe, 0, len(n.Det))
+
+	for _, det := range n.Det {
+		item := domain.ItemNFe{
+			ArquivoXML: nome,
+			ChaveNFe:   chave,
+			NumeroItem: det.NumeroItem,
+			Codigo:     strings.TrimSpace(det.Prod.Codigo),
+			GTIN:       strings.TrimSpace(det.Prod.GTIN),
+			Descricao:  strings.TrimSpace(det.Prod.Descricao),
+			NCM:        strings.TrimSpace(det.Prod.NCM),
+			CEST:       strings.TrimSpace(det.Prod.CEST),
+		}
+		if strings.EqualFold(item.GTIN, "SEM GTIN") {
+			item.GTIN = ""
+		}
+		for _, grupo := range det.Imposto.ICMS.Grupos {
+			item.Origem = strings.TrimSpace(grupo.Origem)
+			item.CST = strings.TrimSpace(grupo.CST)
+			item.CSOSN = strings.TrimSpace(grupo.CSOSN)
+		}
+		itens = append(itens, item)
+	}
+
+	return itens
+}
@@ -0,0 +1,200 @@
+package nfe
+
+import (
+	"ParserTrib/internal/domain"
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const chaveTeste = "41240112345678000199550010000001231000001234"
+
+// xmlNFe monta um nfeProc com um item por grupo det informado
+func xmlNFe(dets ...string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?>` +
+		`<nfeProc xmlns="http://www.portalfiscal.inf.br/nfe"><NFe><infNFe Id="NFe` + chaveTeste + `" versao="4.00">` +
+		strings.Join(dets, "") +
+		`</infNFe></NFe></nfeProc>`
+}
+
+func detNFe(numero int, codigo, gtin, descricao, ncm, icms string) string {
+	return fmt.Sprintf(`<det nItem="%d"><prod><cProd>%s</cProd><cEAN>%s</cEAN><xProd>%s</xProd><NCM>%s</NCM></prod>`+
+		`<imposto><ICMS>%s</ICMS></imposto></det>`, numero, codigo, gtin, descricao, ncm, icms)
+}
+
+func TestLerXML(t *testing.T) {
+	casos := []struct {
+		nome  string
+		dados string
+		itens []domain.ItemNFe
+		erro  error
+	}{
+		{
+			nome: "regime normal e Simples Nacional",
+			dados: xmlNFe(
+				detNFe(1, "P1", "7891234567895", "Caneta azul", "96081000", `<ICMS00><orig>0</orig><CST>00</CST></ICMS00>`),
+				detNFe(2, " P2 ", "SEM GTIN", "Lápis", "96091000", `<ICMSSN102><orig>2</orig><CSOSN>102</CSOSN></ICMSSN102>`),
+			),
+			itens: []domain.ItemNFe{
+				{ArquivoXML: "nota.xml", ChaveNFe: chaveTeste, NumeroItem: 1, Codigo: "P1", GTIN: "7891234567895", Descricao: "Caneta azul", NCM: "96081000", Origem: "0", CST: "00"},
+				{ArquivoXML: "nota.xml", ChaveNFe: chaveTeste, NumeroItem: 2, Codigo: "P2", Descricao: "Lápis", NCM: "96091000", Origem: "2", CSOSN: "102"},
+			},
+		},
+		{
+			nome:  "XML sem infNFe",
+			dados: `<procEventoNFe><evento><infEvento Id="ID110111"/></evento></procEventoNFe>`,
+			erro:  errSemNFe,
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			itens, err := LerXML("nota.xml", []byte(caso.dados))
+			if !errors.Is(err, caso.erro) {
+				t.Fatalf("erro = %v, esperado %v", err, caso.erro)
+			}
+			if !slices.Equal(itens, caso.itens) {
+				t.Errorf("itens = %+v, esperado %+v", itens, caso.itens)
+			}
+		})
+	}
+
+	if _, err := LerXML("nota.xml", []byte("<nfeProc><NFe>")); err == nil || errors.Is(err, errSemNFe) {
+		t.Errorf("XML truncado: erro = %v, esperado erro de interpretação", err)
+	}
+}
+
+// criarZIP grava um .zip com os arquivos informados (nome → conteúdo) numa pasta temporária
+func criarZIP(t *testing.T, arquivos map[string]string) string {
+	t.Helper()
+	caminho := filepath.Join(t.TempDir(), "notas.zip")
+	saida, err := os.Create(caminho)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer saida.Close()
+
+	pacote := zip.NewWriter(saida)
+	for nome, conteudo := range arquivos {
+		escritor, err := pacote.Create(nome)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := escritor.Write([]byte(conteudo)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := pacote.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return caminho
+}
+
+func TestCarregarZIP(t *testing.T) {
+	muitos := make(map[string]string, maximoArquivosZIP+1)
+	for i := range maximoArquivosZIP + 1 {
+		muitos[fmt.Sprintf("nota%05d.xml", i)] = ""
+	}
+
+	casos := []struct {
+		nome      string
+		arquivos  map[string]string
+		itens     int
+		xmls      int
+		ignorados int
+		erro      string
+	}{
+		{
+			nome: "XMLs em subpasta, evento ignorado e outras extensões desconsideradas",
+			arquivos: map[string]string{
+				"2024/01/nota.xml":   xmlNFe(detNFe(1, "P1", "SEM GTIN", "Caneta", "96081000", "")),
+				"2024/01/evento.xml": `<procEventoNFe/>`,
+				"leiame.txt":         "notas de janeiro",
+			},
+			itens:     1,
+			xmls:      2,
+			ignorados: 1,
+		},
+		{
+			nome:     "XML acima do limite descompactado",
+			arquivos: map[string]string{"grande.xml": strings.Repeat(" ", tamanhoMaximoXML+1)},
+			erro:     "excede 10 MB descompactado",
+		},
+		{
+			nome:     "arquivos demais no pacote",
+			arquivos: muitos,
+			erro:     fmt.Sprintf("(máximo: %d)", maximoArquivosZIP),
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			lote, err := Carregar(criarZIP(t, caso.arquivos))
+			if caso.erro != "" {
+				if err == nil || !strings.Contains(err.Error(), caso.erro) {
+					t.Fatalf("erro = %v, esperado conter %q", err, caso.erro)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Carregar: %v", err)
+			}
+			if len(lote.Itens) != caso.itens || lote.TotalXMLs != caso.xmls || len(lote.Ignorados) != caso.ignorados {
+				t.Errorf("itens/XMLs/ignorados = %d/%d/%d, esperado %d/%d/%d",
+					len(lote.Itens), lote.TotalXMLs, len(lote.Ignorados), caso.itens, caso.xmls, caso.ignorados)
+			}
+		})
+	}
+}
+
+func TestCarregarZIPTamanhoDeclaradoFalso(t *testing.T) {
+	// O cabeçalho declara 100 bytes, mas o conteúdo comprimido se expande para mais de 10MB
+	var comprimido bytes.Buffer
+	compressor, err := flate.NewWriter(&comprimido, flate.BestCompression)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conteudo := bytes.Repeat([]byte(" "), tamanhoMaximoXML+1)
+	compressor.Write(conteudo)
+	compressor.Close()
+
+	caminho := filepath.Join(t.TempDir(), "notas.zip")
+	saida, err := os.Create(caminho)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pacote := zip.NewWriter(saida)
+	escritor, err := pacote.CreateRaw(&zip.FileHeader{
+		Name:               "falso.xml",
+		Method:             zip.Deflate,
+		CRC32:              crc32.ChecksumIEEE(conteudo),
+		CompressedSize64:   uint64(comprimido.Len()),
+		UncompressedSize64: 100,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := escritor.Write(comprimido.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if err := pacote.Close(); err != nil {
+		t.Fatal(err)
+	}
+	saida.Close()
+
+	lote, err := Carregar(caminho)
+	if err == nil || !strings.Contains(err.Error(), "falso.xml") {
+		t.Fatalf("erro = %v, esperado erro de leitura de 'falso.xml'", err)
+	}
+	if lote.TotalXMLs != 0 {
+		t.Errorf("totalXMLs = %d, esperado nenhum XML lido", lote.TotalXMLs)
+	}
+}
@@ -0,0 +1,106 @@
+package reforma
+
+// Tabela local e versionada dos códigos da reforma tributária do consumo: CST do IBS/CBS e
+// classificação tributária (cClassTrib), com o CST a que cada classificação pertence
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+)
+
+var (
+	regexCST       = regexp.MustCompile(`^\d{3}$`)
+	regexClassTrib = regexp.MustCompile(`^\d{6}$`)
+)
+
+// Codigo é um CST ou uma classificação da tabela
+type Codigo struct {
+	Codigo    string `json:"codigo"`
+	CST       string `json:"cst,omitempty"` // só nas classificações
+	Descricao string `json:"descricao"`
+}
+
+// arquivoTabela é o formato do JSON da tabela
+type arquivoTabela struct {
+	Versao     string   `json:"versao"`
+	Vigencia   string   `json:"vigencia"`
+	CSTs       []Codigo `json:"cst"`
+	ClassTribs []Codigo `json:"cClassTrib"`
+}
+
+// Tabela indexa os CSTs e as classificações de uma versão da tabela
+type Tabela struct {
+	Versao     string
+	Vigencia   string
+	csts       map[string]Codigo
+	classTribs map[string]Codigo
+}
+
+// Carregar lê a tabela em JSON. Exemplo:
+//
+//	{"versao": "1.0", "vigencia": "2026-01-01",
+//	 "cst": [{"codigo": "000", "descricao": "Tributação integral"}],
+//	 "cClassTrib": [{"codigo": "000001", "cst": "000", "descricao": "Situações tributadas integralmente"}]}
+//
+// Códigos fora do formato (CST com 3 dígitos, cClassTrib com 6) e classificações de CST ausente
+// na própria tabela tornam o arquivo inválido.
+func Carregar(caminho string) (*Tabela, error) {
+	dados, err := os.ReadFile(caminho)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler tabela IBS/CBS: %w", err)
+	}
+
+	var arquivo arquivoTabela
+	if err := json.Unmarshal(dados, &arquivo); err != nil {
+		return nil, fmt.Errorf("erro ao interpretar tabela IBS/CBS '%s': %w", caminho, err)
+	}
+	if arquivo.Versao == "" {
+		return nil, fmt.Errorf("tabela IBS/CBS '%s' sem o campo versao", caminho)
+	}
+
+	tabela := &Tabela{
+		Versao:     arquivo.Versao,
+		Vigencia:   arquivo.Vigencia,
+		csts:       make(map[string]Codigo, len(arquivo.CSTs)),
+		classTribs: make(map[string]Codigo, len(arquivo.ClassTribs)),
+	}
+	for _, cst := range arquivo.CSTs {
+		if !regexCST.MatchString(cst.Codigo) {
+			return nil, fmt.Errorf("tabela IBS/CBS '%s': CST '%s' deve ter 3 dígitos", caminho, cst.Codigo)
+		}
+		tabela.csts[cst.Codigo] = cst
+	}
+	for _, classificacao := range arquivo.ClassTribs {
+		if !regexClassTrib.MatchString(classificacao.Codigo) {
+			return nil, fmt.Errorf("tabela IBS/CBS '%s': cClassTrib '%s' deve ter 6 dígitos", caminho, classificacao.Codigo)
+		}
+		if _, ok := tabela.csts[classificacao.CST]; !ok {
+			return nil, fmt.Errorf("tabela IBS/CBS '%s': cClassTrib '%s' aponta para o CST '%s', ausente da tabela", caminho, classificacao.Codigo, classificacao.CST)
+		}
+		tabela.classTribs[classificacao.Codigo] = classificacao
+	}
+	if len(tabela.csts) == 0 || len(tabela.classTribs) == 0 {
+		return nil, fmt.Errorf("tabela IBS/CBS '%s' sem CSTs ou sem classificações", caminho)
+	}
+
+	return tabela, nil
+}
+
+// CST devolve o CST do IBS/CBS com o código informado
+func (t *Tabela) CST(codigo string) (Codigo, bool) {
+	cst, ok := t.csts[codigo]
+	return cst, ok
+}
+
+// ClassTrib devolve a classificação tributária com o código informado
+func (t *Tabela) ClassTrib(codigo string) (Codigo, bool) {
+	classificacao, ok := t.classTribs[codigo]
+	return classificacao, ok
+}
+
+// Total devolve a quantidade de CSTs e de classificações carregados
+func (t *Tabela) Total() (csts, classTribs int) {
+	return len(t.csts), len(t.classTribs)
+}
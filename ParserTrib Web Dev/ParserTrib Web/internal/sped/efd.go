@@ -0,0 +1,67 @@
+package sped
+
+import (
+	"ParserTrib/internal/domain"
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// LerEFD lê os registros 0200 de um arquivo SPED EFD ICMS/IPI. O arquivo da EFD é gravado em
+// ISO-8859-1 pelo PVA; conteúdo que não é UTF-8 válido é convertido antes da leitura.
+func LerEFD(caminho string) ([]domain.Item0200, error) {
+	dados, err := os.ReadFile(caminho)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler arquivo EFD: %w", err)
+	}
+	if !utf8.Valid(dados) {
+		dados = latin1ParaUTF8(dados)
+	}
+
+	var itens []domain.Item0200
+	scanner := bufio.NewScanner(bytes.NewReader(dados))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for numero := 1; scanner.Scan(); numero++ {
+		linha := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(linha, "|0200|") {
+			continue
+		}
+
+		// |0200|COD_ITEM|DESCR_ITEM|COD_BARRA|COD_ANT_ITEM|UNID_INV|TIPO_ITEM|COD_NCM|EX_IPI|COD_GEN|COD_LST|ALIQ_ICMS|CEST|
+		campos := strings.Split(strings.Trim(linha, "|"), "|")
+		if len(campos) < 8 {
+			return nil, fmt.Errorf("registro 0200 incompleto na linha %d do arquivo EFD", numero)
+		}
+		item := domain.Item0200{
+			LinhaArquivo: numero,
+			CodItem:      strings.TrimSpace(campos[1]),
+			Descricao:    strings.TrimSpace(campos[2]),
+			TipoItem:     strings.TrimSpace(campos[6]),
+			NCM:          strings.TrimSpace(campos[7]),
+		}
+		if len(campos) > 12 {
+			item.CEST = strings.TrimSpace(campos[12])
+		}
+		itens = append(itens, item)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao ler arquivo EFD: %w", err)
+	}
+	if len(itens) == 0 {
+		return nil, fmt.Errorf("nenhum registro 0200 encontrado em '%s'", caminho)
+	}
+
+	return itens, nil
+}
+
+// latin1ParaUTF8 converte ISO-8859-1 (cada byte é o próprio code point) para UTF-8
+func latin1ParaUTF8(dados []byte) []byte {
+	convertido := make([]byte, 0, len(dados)+len(dados)/8)
+	for _, b := range dados {
+		convertido = utf8.AppendRune(convertido, rune(b))
+	}
+	return convertido
+}
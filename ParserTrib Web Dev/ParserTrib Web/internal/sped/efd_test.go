@@ -0,0 +1,68 @@
+package sped
+
+import (
+	"ParserTrib/internal/domain"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLerEFD(t *testing.T) {
+	casos := []struct {
+		nome     string
+		conteudo []byte
+		itens    []domain.Item0200
+		erro     string
+	}{
+		{
+			nome: "registros 0200 com e sem CEST",
+			conteudo: []byte("|0000|017|0|01012024|31012024|EMPRESA|\r\n" +
+				"|0200|P1|Caneta azul|7891234567895||UN|00|96081000||||18|1234567|\r\n" +
+				"|0200|P2|Lápis|||UN|00|96091000|||\r\n"),
+			itens: []domain.Item0200{
+				{LinhaArquivo: 2, CodItem: "P1", Descricao: "Caneta azul", TipoItem: "00", NCM: "96081000", CEST: "1234567"},
+				{LinhaArquivo: 3, CodItem: "P2", Descricao: "Lápis", TipoItem: "00", NCM: "96091000"},
+			},
+		},
+		{
+			nome:     "arquivo em ISO-8859-1",
+			conteudo: []byte("|0200|P1|L\xe1pis|||UN|00|96091000|||\r\n"),
+			itens:    []domain.Item0200{{LinhaArquivo: 1, CodItem: "P1", Descricao: "Lápis", TipoItem: "00", NCM: "96091000"}},
+		},
+		{
+			nome:     "registro incompleto",
+			conteudo: []byte("|0200|P1|Caneta|\r\n"),
+			erro:     "registro 0200 incompleto na linha 1",
+		},
+		{
+			nome:     "sem registros 0200",
+			conteudo: []byte("|0000|017|0|\r\n|9999|1|\r\n"),
+			erro:     "nenhum registro 0200 encontrado",
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			caminho := filepath.Join(t.TempDir(), "efd.txt")
+			if err := os.WriteFile(caminho, caso.conteudo, 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			itens, err := LerEFD(caminho)
+			if caso.erro != "" {
+				if err == nil || !strings.Contains(err.Error(), caso.erro) {
+					t.Fatalf("erro = %v, esperado conter %q", err, caso.erro)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LerEFD: %v", err)
+			}
+			if !slices.Equal(itens, caso.itens) {
+				t.Errorf("itens = %+v, esperado %+v", itens, caso.itens)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package sped
+
+// Exportação dos registros 0190 (unidades de medida) e 0200 (identificação do item) do SPED Fiscal
+// (EFD ICMS/IPI) a partir da planilha de produtos já validada
+
+import (
+	"ParserTrib/internal/domain"
+	"ParserTrib/internal/excel"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Códigos dos problemas encontrados na montagem dos registros (específicos do layout da EFD)
+const (
+	ProblemaObrigatorio = "SPED001"
+	ProblemaTamanho     = "SPED002"
+	ProblemaCaractere   = "SPED003"
+	ProblemaFormato     = "SPED004"
+	ProblemaDuplicado   = "SPED005"
+)
+
+// TipoSPED identifica, em ErroValidacao.Tipo, os problemas de exportação
+const TipoSPED = "SPED"
+
+// campo0200 descreve um campo do registro 0200 preenchido a partir de uma coluna da planilha
+type campo0200 struct {
+	nome        string
+	coluna      string
+	tamanho     int // tamanho máximo (0 = sem limite no layout)
+	obrigatorio bool
+	formato     *regexp.Regexp // nil = texto livre
+}
+
+// campos0200 segue a ordem do leiaute: REG, COD_ITEM, DESCR_ITEM, COD_BARRA, COD_ANT_ITEM, UNID_INV,
+// TIPO_ITEM, COD_NCM, EX_IPI, COD_GEN, COD_LST, ALIQ_ICMS, CEST. Os campos sem coluna na planilha
+// (COD_ANT_ITEM, EX_IPI, COD_GEN, ALIQ_ICMS) saem vazios.
+var campos0200 = []campo0200{
+	{"COD_ITEM", excel.ColunaCodigo, 60, true, nil},
+	{"DESCR_ITEM", excel.ColunaDescricao, 0, true, nil},
+	{"COD_BARRA", excel.ColunaGTIN, 0, false, nil},
+	{"COD_ANT_ITEM", "", 60, false, nil},
+	{"UNID_INV", excel.ColunaUnidadeComercial, 6, true, nil},
+	{"TIPO_ITEM", excel.ColunaTipoItem, 2, true, regexp.MustCompile(`^\d{2}$`)},
+	{"COD_NCM", excel.ColunaNCM, 8, false, regexp.MustCompile(`^\d{8}$`)},
+	{"EX_IPI", "", 3, false, nil},
+	{"COD_GEN", "", 2, false, nil},
+	{"COD_LST", excel.ColunaServico, 5, false, regexp.MustCompile(`^\d{2}\.\d{2}$`)},
+	{"ALIQ_ICMS", "", 0, false, nil},
+	{"CEST", excel.ColunaCEST, 7, false, regexp.MustCompile(`^\d{7}$`)},
+}
+
+// descricoesUnidades traz a descrição (campo DESCR do 0190) das unidades da tabela padrão
+var descricoesUnidades = func() map[string]string {
+	descricoes := make(map[string]string, len(excel.TabelaUnidades))
+	for _, unidade := range excel.TabelaUnidades {
+		descricoes[unidade.Codigo] = unidade.Descricao
+	}
+	return descricoes
+}()
+
+// Exportacao guarda os registros montados, prontos para gravar
+type Exportacao struct {
+	Registros0190 []string
+	Registros0200 []string
+}
+
+// Gerar monta os registros 0190 e 0200 a partir das linhas da planilha. Os problemas de leiaute
+// (tamanho, formato, caractere "|", campo obrigatório, COD_ITEM repetido) são devolvidos à parte;
+// havendo algum, a exportação não deve ser gravada.
+func Gerar(rows [][]string, cabecalhos []string) (Exportacao, []domain.ErroValidacao) {
+	var exportacao Exportacao
+	var problemas []domain.ErroValidacao
+
+	indices := make(map[string]int, len(cabecalhos))
+	for i, cab := range cabecalhos {
+		indices[cab] = i
+	}
+
+	unidades := make(map[string]bool)
+	codigos := make(map[string]int)
+
+	for i := 1; i < len(rows); i++ {
+		linha := i + 1
+		valores := make([]string, 0, len(campos0200)+1)
+		valores = append(valores, "0200")
+
+		for _, campo := range campos0200 {
+			valor := ""
+			indice, existe := indices[campo.coluna]
+			if existe && indice < len(rows[i]) {
+				valor = limpar(rows[i][indice])
+			}
+
+			problema := func(codigo, mensagem string) {
+				coluna := ""
+				if existe {
+					coluna, _ = excelize.ColumnNumberToName(indice + 1)
+				}
+				problemas = append(problemas, domain.ErroValidacao{
+					Linha:      linha,
+					Coluna:     coluna,
+					NomeColuna: campo.coluna,
+					Tipo:       TipoSPED,
+					Codigo:     codigo,
+					Severidade: domain.SeveridadeErro,
+					Mensagem:   fmt.Sprintf("%s: %s", campo.nome, mensagem),
+				})
+			}
+
+			switch {
+			case valor == "":
+				if campo.obrigatorio {
+					problema(ProblemaObrigatorio, "campo obrigatório no registro 0200")
+				}
+			case strings.Contains(valor, "|"):
+				problema(ProblemaCaractere, "o caractere '|' é o separador do arquivo e não pode aparecer no conteúdo")
+			case campo.tamanho > 0 && utf8.RuneCountInString(valor) > campo.tamanho:
+				problema(ProblemaTamanho, fmt.Sprintf("máximo de %d caracteres (atual: %d)", campo.tamanho, utf8.RuneCountInString(valor)))
+			case campo.formato != nil && !campo.formato.MatchString(valor):
+				problema(ProblemaFormato, fmt.Sprintf("formato inválido para o leiaute (atual: '%s')", valor))
+			}
+
+			if campo.nome == "COD_ITEM" && valor != "" {
+				if anterior, repetido := codigos[valor]; repetido {
+					problema(ProblemaDuplicado, fmt.Sprintf("código '%s' já usado na linha %d; COD_ITEM deve ser único no arquivo", valor, anterior))
+				} else {
+					codigos[valor] = linha
+				}
+			}
+			if campo.nome == "UNID_INV" && valor != "" {
+				unidades[strings.ToUpper(valor)] = true
+			}
+
+			valores = append(valores, valor)
+		}
+
+		exportacao.Registros0200 = append(exportacao.Registros0200, "|"+strings.Join(valores, "|")+"|")
+	}
+
+	lista := make([]string, 0, len(unidades))
+	for unidade := range unidades {
+		lista = append(lista, unidade)
+	}
+	sort.Strings(lista)
+	for _, unidade := range lista {
+		descricao, ok := descricoesUnidades[unidade]
+		if !ok {
+			descricao = unidade
+		}
+		exportacao.Registros0190 = append(exportacao.Registros0190, "|0190|"+unidade+"|"+descricao+"|")
+	}
+
+	return exportacao, problemas
+}
+
+// Escrever grava os registros no formato da EFD: 0190 antes de 0200, um por linha, com CRLF
+func (e Exportacao) Escrever(w io.Writer) error {
+	for _, registros := range [][]string{e.Registros0190, e.Registros0200} {
+		for _, registro := range registros {
+			if _, err := io.WriteString(w, registro+"\r\n"); err != nil {
+				return fmt.Errorf("erro ao gravar registros SPED: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// limpar remove espaços nas pontas e quebras de linha (o registro ocupa uma única linha)
+func limpar(valor string) string {
+	valor = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ", "\t", " ").Replace(valor)
+	return strings.TrimSpace(valor)
+}
@@ -0,0 +1,98 @@
+package sped
+
+import (
+	"ParserTrib/internal/excel"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var cabecalhosTeste = []string{
+	excel.ColunaCodigo, excel.ColunaDescricao, excel.ColunaGTIN, excel.ColunaUnidadeComercial,
+	excel.ColunaTipoItem, excel.ColunaNCM, excel.ColunaServico, excel.ColunaCEST,
+}
+
+func TestGerar(t *testing.T) {
+	casos := []struct {
+		nome      string
+		linhas    [][]string
+		registros []string
+		unidades  []string
+		problemas []string
+	}{
+		{
+			nome:      "item completo",
+			linhas:    [][]string{{"P1", "Caneta azul", "7891234567895", "un", "00", "96081000", "", "1234567"}},
+			registros: []string{"|0200|P1|Caneta azul|7891234567895||un|00|96081000|||||1234567|"},
+			unidades:  []string{"|0190|UN|UNIDADE|"},
+		},
+		{
+			nome:      "quebra de linha vira espaço",
+			linhas:    [][]string{{"P1", " Caneta\nazul ", "", "UN", "00", "", "", ""}},
+			registros: []string{"|0200|P1|Caneta azul|||UN|00|||||||"},
+			unidades:  []string{"|0190|UN|UNIDADE|"},
+		},
+		{
+			nome:      "unidade fora da tabela usa o próprio código como descrição",
+			linhas:    [][]string{{"P1", "Caneta", "", "ZZ", "00", "", "", ""}},
+			registros: []string{"|0200|P1|Caneta|||ZZ|00|||||||"},
+			unidades:  []string{"|0190|ZZ|ZZ|"},
+		},
+		{
+			nome:      "campo obrigatório vazio",
+			linhas:    [][]string{{"P1", "", "", "UN", "00", "", "", ""}},
+			problemas: []string{"2 Descrição SPED001"},
+		},
+		{
+			nome:      "tamanho, caractere e formato",
+			linhas:    [][]string{{"P1", "Caneta | azul", "", "UNIDADE", "0", "9608.10.00", "1.07", ""}},
+			problemas: []string{"2 Descrição SPED003", "2 Unidade Comercial SPED002", "2 Tipo Item SPED004", "2 NCM SPED002", "2 Código Serviço SPED004"},
+		},
+		{
+			nome:      "código repetido",
+			linhas:    [][]string{{"P1", "Caneta", "", "UN", "00", "", "", ""}, {"P1", "Lápis", "", "UN", "00", "", "", ""}},
+			problemas: []string{"3 Código SPED005"},
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			rows := append([][]string{cabecalhosTeste}, caso.linhas...)
+			exportacao, problemas := Gerar(rows, cabecalhosTeste)
+
+			var resumo []string
+			for _, p := range problemas {
+				resumo = append(resumo, fmt.Sprintf("%d %s %s", p.Linha, p.NomeColuna, p.Codigo))
+			}
+			if !slices.Equal(resumo, caso.problemas) {
+				t.Errorf("problemas = %q, esperado %q", resumo, caso.problemas)
+			}
+			if caso.problemas != nil {
+				return
+			}
+			if !slices.Equal(exportacao.Registros0200, caso.registros) {
+				t.Errorf("registros 0200 = %q, esperado %q", exportacao.Registros0200, caso.registros)
+			}
+			if !slices.Equal(exportacao.Registros0190, caso.unidades) {
+				t.Errorf("registros 0190 = %q, esperado %q", exportacao.Registros0190, caso.unidades)
+			}
+		})
+	}
+}
+
+func TestEscrever(t *testing.T) {
+	exportacao := Exportacao{
+		Registros0190: []string{"|0190|UN|UNIDADE|"},
+		Registros0200: []string{"|0200|P1|Caneta|||UN|00||||||"},
+	}
+
+	var saida strings.Builder
+	if err := exportacao.Escrever(&saida); err != nil {
+		t.Fatalf("Escrever: %v", err)
+	}
+	esperado := "|0190|UN|UNIDADE|\r\n|0200|P1|Caneta|||UN|00||||||\r\n"
+	if saida.String() != esperado {
+		t.Errorf("saída = %q, esperado %q", saida.String(), esperado)
+	}
+}
@@ -0,0 +1,62 @@
+package texto
+
+// Funções de normalização de texto usadas para comparar descrições de produtos
+
+import (
+	"strings"
+	"unicode"
+)
+
+// acentos mapeia letras acentuadas (minúsculas) para a letra base
+var acentos = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// unidadesVenda são sufixos de unidade de venda que não diferenciam o produto ("CANETA AZUL UN")
+var unidadesVenda = map[string]bool{
+	"un": true, "und": true, "unid": true, "unidade": true,
+	"pc": true, "pcs": true, "peca": true, "pct": true, "pacote": true,
+	"cx": true, "caixa": true, "cj": true, "kit": true,
+}
+
+// RemoverAcentos converte para minúsculas e troca letras acentuadas pela letra base
+func RemoverAcentos(s string) string {
+	return acentos.Replace(strings.ToLower(s))
+}
+
+// Tokens devolve as palavras do texto em minúsculas, sem acentos e sem pontuação
+func Tokens(s string) []string {
+	return strings.FieldsFunc(RemoverAcentos(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// NormalizarDescricao gera a chave de comparação de uma descrição: sem diferença de caixa,
+// acentos, pontuação e espaços, e sem unidades de venda no final
+func NormalizarDescricao(s string) string {
+	tokens := Tokens(s)
+	for len(tokens) > 1 && unidadesVenda[tokens[len(tokens)-1]] {
+		tokens = tokens[:len(tokens)-1]
+	}
+	return strings.Join(tokens, " ")
+}
+
+// Jaccard é a razão entre os termos em comum e o total de termos distintos das duas descrições
+func Jaccard(a, b map[string]bool) float64 {
+	comuns := 0
+	for termo := range a {
+		if b[termo] {
+			comuns++
+		}
+	}
+	uniao := len(a) + len(b) - comuns
+	if uniao == 0 {
+		return 0
+	}
+	return float64(comuns) / float64(uniao)
+}
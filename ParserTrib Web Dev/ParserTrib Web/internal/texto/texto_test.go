@@ -0,0 +1,63 @@
+package texto
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTokens(t *testing.T) {
+	obtidos := Tokens("Óleo de SOJA/Girassol, 900ml (Açaí)")
+	esperados := []string{"oleo", "de", "soja", "girassol", "900ml", "acai"}
+	if !slices.Equal(obtidos, esperados) {
+		t.Errorf("Tokens = %q, esperado %q", obtidos, esperados)
+	}
+}
+
+func TestNormalizarDescricao(t *testing.T) {
+	casos := []struct {
+		entrada  string
+		esperado string
+	}{
+		{"Caneta Azul", "caneta azul"},
+		{"  CANETA   AZUL  ", "caneta azul"},
+		{"Sabão em Pó", "sabao em po"},
+		{"CANETA-AZUL.", "caneta azul"},
+		{"CANETA AZUL UN", "caneta azul"},
+		{"CANETA AZUL CX 12 UN", "caneta azul cx 12"},
+		{"KIT PCT", "kit"}, // a última palavra nunca é removida
+		{"", ""},
+		{"---", ""},
+	}
+
+	for _, caso := range casos {
+		if obtido := NormalizarDescricao(caso.entrada); obtido != caso.esperado {
+			t.Errorf("NormalizarDescricao(%q) = %q, esperado %q", caso.entrada, obtido, caso.esperado)
+		}
+	}
+}
+
+func TestJaccard(t *testing.T) {
+	conjunto := func(termos ...string) map[string]bool {
+		m := make(map[string]bool)
+		for _, termo := range termos {
+			m[termo] = true
+		}
+		return m
+	}
+
+	casos := []struct {
+		a, b     map[string]bool
+		esperado float64
+	}{
+		{conjunto("parafuso", "sextavado"), conjunto("parafuso", "sextavado"), 1},
+		{conjunto("parafuso", "sextavado", "aco"), conjunto("parafuso", "sextavado", "aco", "zincado"), 0.75},
+		{conjunto("parafuso"), conjunto("porca"), 0},
+		{conjunto(), conjunto(), 0},
+	}
+
+	for _, caso := range casos {
+		if obtido := Jaccard(caso.a, caso.b); obtido != caso.esperado {
+			t.Errorf("Jaccard(%v, %v) = %v, esperado %v", caso.a, caso.b, obtido, caso.esperado)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package logger
 
 import (
 	"ParserTrib/internal/domain"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,7 +10,7 @@ import (
 	"time"
 )
 
-// SalvarLog cria e salva o arquivo de log com timestamp e as 4 seções de erros
+// SalvarLog cria e salva o arquivo de log com timestamp e uma seção por categoria de erro
 func SalvarLog(caminhoArquivoOriginal string, diretorioLogs string, resultado domain.ResultadoValidacaoCompleto) (string, error) {
 	caminhoLog, err := gerarCaminhoLog(caminhoArquivoOriginal, diretorioLogs)
 	if err != nil {
@@ -40,7 +41,7 @@ func gerarCaminhoLog(caminhoArquivo string, diretorioLogs string) (string, error
 	return caminhoCompleto, nil
 }
 
-// escreverLog escreve as mensagens de erro no arquivo com 4 seções separadas
+// escreverLog escreve as mensagens de erro no arquivo com uma seção por categoria
 func escreverLog(caminhoCompleto string, resultado domain.ResultadoValidacaoCompleto) error {
 	f, err := os.Create(caminhoCompleto)
 	if err != nil {
@@ -54,22 +55,33 @@ func escreverLog(caminhoCompleto string, resultado domain.ResultadoValidacaoComp
 	f.WriteString(strings.Repeat("=", 80) + "\n\n")
 
 	f.WriteString("RESUMO:\n")
-	f.WriteString(fmt.Sprintf("- Total de células vazias: %d\n", len(resultado.ErrosVazias)))
-	f.WriteString(fmt.Sprintf("- Total de erros NCM: %d\n", len(resultado.ErrosNCM)))
-	f.WriteString(fmt.Sprintf("- Total de erros CST Origem: %d\n", len(resultado.ErrosCSTOrigem)))
-	f.WriteString(fmt.Sprintf("- Total de erros CSOSN: %d\n", len(resultado.ErrosCSOSN)))
-	f.WriteString(fmt.Sprintf("- Total de erros Tipo Item: %d\n", len(resultado.ErrosTipoItem)))
+	for _, categoria := range resultado.Categorias() {
+		f.WriteString(fmt.Sprintf("- Total de %s: %d\n", categoria.Rotulo, len(categoria.Erros)))
+	}
 
-	f.WriteString(fmt.Sprintf("- Total geral de erros: %d\n", resultado.TotalErros()))
+	porSeveridade := resultado.PorSeveridade()
+	f.WriteString(fmt.Sprintf("- Total geral de erros: %d (avisos: %d | informativos: %d)\n",
+		resultado.TotalErros(),
+		porSeveridade[domain.SeveridadeAviso],
+		porSeveridade[domain.SeveridadeInfo]))
+	preenchidas, exigidas, cobertura := resultado.CoberturaObrigatorias()
+	f.WriteString(fmt.Sprintf("- Cobertura dos campos obrigatórios: %.1f%% (%d de %d células)\n", cobertura, preenchidas, exigidas))
+	f.WriteString(fmt.Sprintf("- Grupos de produtos duplicados: %d (%d divergentes)\n", len(resultado.Duplicados), resultado.GruposDivergentes()))
+	f.WriteString(fmt.Sprintf("- Avisos de consistência: %d\n", len(resultado.AvisosConsistencia)))
+	f.WriteString(fmt.Sprintf("- Ignorados pelo baseline: %d\n", len(resultado.Ignorados)))
 	f.WriteString(fmt.Sprintf("- Tempo de execução: %v\n", resultado.TempoExecucao))
 	f.WriteString("\n")
 
-	if len(resultado.ErrosVazias) > 0 {
+	for _, categoria := range resultado.Categorias() {
+		if len(categoria.Erros) == 0 {
+			continue
+		}
+
 		f.WriteString(strings.Repeat("=", 80) + "\n")
-		f.WriteString(fmt.Sprintf("CÉLULAS VAZIAS (%d)\n", len(resultado.ErrosVazias)))
+		f.WriteString(fmt.Sprintf("%s (%d)\n", categoria.Titulo, len(categoria.Erros)))
 		f.WriteString(strings.Repeat("=", 80) + "\n")
 
-		for _, erro := range resultado.ErrosVazias {
+		for _, erro := range categoria.Erros {
 			_, err := f.WriteString(erro.String() + "\n")
 			if err != nil {
 				return fmt.Errorf("erro ao escrever no log: %w", err)
@@ -78,62 +90,71 @@ func escreverLog(caminhoCompleto string, resultado domain.ResultadoValidacaoComp
 		f.WriteString("\n")
 	}
 
-	if len(resultado.ErrosNCM) > 0 {
+	if len(resultado.Perfil) > 0 {
 		f.WriteString(strings.Repeat("=", 80) + "\n")
-		f.WriteString(fmt.Sprintf("ERROS DE VALIDAÇÃO NCM (%d)\n", len(resultado.ErrosNCM)))
+		f.WriteString(fmt.Sprintf("PERFIL DAS COLUNAS (%d)\n", len(resultado.Perfil)))
 		f.WriteString(strings.Repeat("=", 80) + "\n")
 
-		for _, erro := range resultado.ErrosNCM {
-			_, err := f.WriteString(erro.String() + "\n")
-			if err != nil {
+		for _, perfil := range resultado.Perfil {
+			if _, err := f.WriteString(perfil.String() + "\n"); err != nil {
 				return fmt.Errorf("erro ao escrever no log: %w", err)
 			}
 		}
 		f.WriteString("\n")
 	}
 
-	if len(resultado.ErrosCSTOrigem) > 0 {
+	if len(resultado.Cobertura) > 0 {
 		f.WriteString(strings.Repeat("=", 80) + "\n")
-		f.WriteString(fmt.Sprintf("ERROS DE VALIDAÇÃO CST ORIGEM (%d)\n", len(resultado.ErrosCSTOrigem)))
+		f.WriteString(fmt.Sprintf("COBERTURA DOS CAMPOS OBRIGATÓRIOS (%d colunas)\n", len(resultado.Cobertura)))
 		f.WriteString(strings.Repeat("=", 80) + "\n")
 
-		for _, erro := range resultado.ErrosCSTOrigem {
-			_, err := f.WriteString(erro.String() + "\n")
-			if err != nil {
+		for _, coluna := range resultado.Cobertura {
+			if _, err := f.WriteString(coluna.String() + "\n"); err != nil {
 				return fmt.Errorf("erro ao escrever no log: %w", err)
 			}
 		}
 		f.WriteString("\n")
 	}
 
-	if len(resultado.ErrosCSOSN) > 0 {
+	if len(resultado.Duplicados) > 0 {
 		f.WriteString(strings.Repeat("=", 80) + "\n")
-		f.WriteString(fmt.Sprintf("ERROS DE VALIDAÇÃO CSOSN (%d)\n", len(resultado.ErrosCSOSN)))
+		f.WriteString(fmt.Sprintf("PRODUTOS DUPLICADOS (%d grupos)\n", len(resultado.Duplicados)))
 		f.WriteString(strings.Repeat("=", 80) + "\n")
 
-		for _, erro := range resultado.ErrosCSOSN {
-			_, err := f.WriteString(erro.String() + "\n")
-			if err != nil {
+		for _, grupo := range resultado.Duplicados {
+			if _, err := f.WriteString(grupo.String() + "\n"); err != nil {
 				return fmt.Errorf("erro ao escrever no log: %w", err)
 			}
 		}
 		f.WriteString("\n")
 	}
 
-	if len(resultado.ErrosTipoItem) > 0 {
+	if len(resultado.AvisosConsistencia) > 0 {
 		f.WriteString(strings.Repeat("=", 80) + "\n")
-		f.WriteString(fmt.Sprintf("ERROS DE VALIDAÇÃO TIPO ITEM (%d)\n", len(resultado.ErrosTipoItem)))
+		f.WriteString(fmt.Sprintf("AVISOS DE CONSISTÊNCIA ENTRE PRODUTOS SEMELHANTES (%d)\n", len(resultado.AvisosConsistencia)))
 		f.WriteString(strings.Repeat("=", 80) + "\n")
 
-		for _, erro := range resultado.ErrosTipoItem {
-			_, err := f.WriteString(erro.String() + "\n")
-			if err != nil {
+		for _, aviso := range resultado.AvisosConsistencia {
+			if _, err := f.WriteString(aviso.String() + "\n"); err != nil {
+				return fmt.Errorf("erro ao escrever no log: %w", err)
+			}
+		}
+		f.WriteString("\n")
+	}
+
+	if len(resultado.Ignorados) > 0 {
+		f.WriteString(strings.Repeat("=", 80) + "\n")
+		f.WriteString(fmt.Sprintf("IGNORADOS (%d)\n", len(resultado.Ignorados)))
+		f.WriteString(strings.Repeat("=", 80) + "\n")
+
+		for _, ignorado := range resultado.Ignorados {
+			if _, err := f.WriteString(ignorado.String() + "\n"); err != nil {
 				return fmt.Errorf("erro ao escrever no log: %w", err)
 			}
 		}
 		f.WriteString("\n")
 	}
-	
+
 	// Rodapé
 
 	f.WriteString(strings.Repeat("=", 80) + "\n")
@@ -142,3 +163,60 @@ func escreverLog(caminhoCompleto string, resultado domain.ResultadoValidacaoComp
 
 	return nil
 }
+
+// SalvarResultado grava o resultado da validação em JSON (mesmo formato da resposta da API),
+// para gerar o baseline de supressões ou comparar com uma próxima versão da planilha
+func SalvarResultado(caminhoArquivoOriginal string, diretorioLogs string, resultado domain.ResultadoValidacaoCompleto) (string, error) {
+	caminhoLog, err := gerarCaminhoLog(caminhoArquivoOriginal, diretorioLogs)
+	if err != nil {
+		return "", err
+	}
+	caminhoResultado := strings.Replace(caminhoLog, "log_validacao_", "resultado_", 1)
+	caminhoResultado = strings.TrimSuffix(caminhoResultado, ".txt") + ".json"
+
+	dados, err := json.MarshalIndent(resultado.ToRespostaAPI(), "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("erro ao serializar resultado: %w", err)
+	}
+	if err := os.WriteFile(caminhoResultado, dados, 0644); err != nil {
+		return "", fmt.Errorf("erro ao salvar resultado: %w", err)
+	}
+
+	return caminhoResultado, nil
+}
+
+// SalvarLogCorrecoes cria o arquivo de log do modo de correção com todas as células alteradas (antes e depois)
+func SalvarLogCorrecoes(caminhoArquivoOriginal string, diretorioLogs string, caminhoCorrigido string, correcoes []domain.Correcao) (string, error) {
+	caminhoLog, err := gerarCaminhoLog(caminhoArquivoOriginal, diretorioLogs)
+	if err != nil {
+		return "", err
+	}
+	caminhoLog = strings.Replace(caminhoLog, "log_validacao_", "log_correcoes_", 1)
+
+	f, err := os.Create(caminhoLog)
+	if err != nil {
+		return "", fmt.Errorf("erro ao criar arquivo de log: %w", err)
+	}
+	defer f.Close()
+
+	timestamp := time.Now().Format("02/01/2006 15:04:05")
+	f.WriteString(strings.Repeat("=", 80) + "\n")
+	f.WriteString(fmt.Sprintf("RELATÓRIO DE CORREÇÕES AUTOMÁTICAS - %s\n", timestamp))
+	f.WriteString(strings.Repeat("=", 80) + "\n\n")
+
+	f.WriteString(fmt.Sprintf("Arquivo original:  %s\n", caminhoArquivoOriginal))
+	f.WriteString(fmt.Sprintf("Arquivo corrigido: %s\n", caminhoCorrigido))
+	f.WriteString(fmt.Sprintf("Células corrigidas: %d\n\n", len(correcoes)))
+
+	for _, correcao := range correcoes {
+		if _, err := f.WriteString(correcao.String() + "\n"); err != nil {
+			return "", fmt.Errorf("erro ao escrever no log: %w", err)
+		}
+	}
+
+	f.WriteString("\n" + strings.Repeat("=", 80) + "\n")
+	f.WriteString("FIM DO RELATÓRIO\n")
+	f.WriteString(strings.Repeat("=", 80) + "\n")
+
+	return caminhoLog, nil
+}
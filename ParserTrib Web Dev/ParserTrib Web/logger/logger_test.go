@@ -0,0 +1,42 @@
+package logger
+
+import (
+	"ParserTrib/internal/domain"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSalvarLogCorrecoes(t *testing.T) {
+	diretorio := filepath.Join(t.TempDir(), "logs")
+	correcoes := []domain.Correcao{
+		{Linha: 2, Coluna: "B", NomeColuna: "NCM", Regras: "NCM_ZERO_ESQUERDA", Antes: "1012100", Depois: "01012100"},
+		{Linha: 3, Coluna: "D", NomeColuna: "Tipo Item", Regras: "TIPO_ITEM_ZERO_ESQUERDA", Antes: "4", Depois: "04"},
+	}
+
+	caminho, err := SalvarLogCorrecoes("planilhas/produtos.xlsx", diretorio, "planilhas/produtos_corrigido.xlsx", correcoes)
+	if err != nil {
+		t.Fatalf("SalvarLogCorrecoes: %v", err)
+	}
+	if filepath.Dir(caminho) != diretorio || !strings.HasPrefix(filepath.Base(caminho), "log_correcoes_produtos_") {
+		t.Errorf("log gravado em %s, esperado log_correcoes_produtos_<data>.txt em %s", caminho, diretorio)
+	}
+
+	dados, err := os.ReadFile(caminho)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conteudo := string(dados)
+	for _, trecho := range []string{
+		"Arquivo original:  planilhas/produtos.xlsx\n",
+		"Arquivo corrigido: planilhas/produtos_corrigido.xlsx\n",
+		"Células corrigidas: 2\n",
+		"[CORRIGIDO] Linha 2, Coluna B (NCM): '1012100' -> '01012100' [NCM_ZERO_ESQUERDA]\n",
+		"[CORRIGIDO] Linha 3, Coluna D (Tipo Item): '4' -> '04' [TIPO_ITEM_ZERO_ESQUERDA]\n",
+	} {
+		if !strings.Contains(conteudo, trecho) {
+			t.Errorf("log sem o trecho %q", trecho)
+		}
+	}
+}
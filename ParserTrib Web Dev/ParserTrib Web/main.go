@@ -2,24 +2,115 @@ package main
 
 import (
 	"ParserTrib/cmd"
+	"ParserTrib/internal/baseline"
+	"ParserTrib/internal/cbenef"
+	"ParserTrib/internal/comparacao"
 	"ParserTrib/internal/config"
 	"ParserTrib/internal/domain"
 	"ParserTrib/internal/excel"
 	"ParserTrib/internal/filesystem"
 	"ParserTrib/internal/formatter"
+	"ParserTrib/internal/ncm"
+	"ParserTrib/internal/nfe"
+	"ParserTrib/internal/reforma"
+	"ParserTrib/internal/sped"
 	"ParserTrib/logger"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 )
 
 func main() {
 	cfg := config.Nova()
 
-	// Se rodar com argumento "server", sobe a API — senão, modo CLI original
-	if len(os.Args) > 1 && os.Args[1] == "server" {
-		cmd.IniciarServidor(cfg)
-		return
+	// Subcomandos: "server" sobe a API, "validar <arquivo>" valida para CI (código de saída 1 se houver
+	// problemas com severidade erro), "corrigir <arquivo>" aplica as correções automáticas,
+	// "modelo [destino]" gera a planilha modelo, "baseline <resultado.json> [destino]" gera o arquivo de
+	// supressões a partir de um resultado salvo, "comparar <anterior> <atual> [coluna-chave]" compara duas
+	// versões (planilhas ou resultados salvos), "separar <arquivo>" grava as linhas válidas e as rejeitadas
+	// em planilhas separadas, "sped <arquivo> [destino]" exporta os registros 0190/0200 da EFD,
+	// "nfe <arquivo> <xml|pasta|zip>..." confere a planilha com XMLs de NF-e, "conciliar <arquivo> <efd.txt>"
+	// valida e concilia com os registros 0200 de um SPED EFD — sem argumentos, modo CLI original
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "server":
+			cmd.IniciarServidor(cfg)
+			return
+		case "validar":
+			if len(os.Args) < 3 {
+				fmt.Println("Uso: ParserTrib validar <arquivo.xlsx>")
+				os.Exit(2)
+			}
+			os.Exit(validar(os.Args[2], cfg))
+		case "corrigir":
+			if len(os.Args) < 3 {
+				fmt.Println("Uso: ParserTrib corrigir <arquivo.xlsx>")
+				os.Exit(2)
+			}
+			corrigir(os.Args[2], cfg)
+			return
+		case "baseline":
+			if len(os.Args) < 3 {
+				fmt.Println("Uso: ParserTrib baseline <resultado.json> [destino]")
+				os.Exit(2)
+			}
+			destino := cfg.Baseline
+			if len(os.Args) > 3 {
+				destino = os.Args[3]
+			}
+			gerarBaseline(os.Args[2], destino)
+			return
+		case "comparar":
+			if len(os.Args) < 4 {
+				fmt.Println("Uso: ParserTrib comparar <anterior.xlsx|.json> <atual.xlsx|.json> [coluna-chave]")
+				os.Exit(2)
+			}
+			if len(os.Args) > 4 {
+				cfg.ColunaChave = os.Args[4]
+			}
+			comparar(os.Args[2], os.Args[3], cfg)
+			return
+		case "separar":
+			if len(os.Args) < 3 {
+				fmt.Println("Uso: ParserTrib separar <arquivo.xlsx>")
+				os.Exit(2)
+			}
+			separar(os.Args[2], cfg)
+			return
+		case "sped":
+			if len(os.Args) < 3 {
+				fmt.Println("Uso: ParserTrib sped <arquivo.xlsx> [destino.txt]")
+				os.Exit(2)
+			}
+			destino := strings.TrimSuffix(os.Args[2], filepath.Ext(os.Args[2])) + "_0200.txt"
+			if len(os.Args) > 3 {
+				destino = os.Args[3]
+			}
+			os.Exit(exportarSPED(os.Args[2], destino, cfg))
+		case "nfe":
+			if len(os.Args) < 4 {
+				fmt.Println("Uso: ParserTrib nfe <arquivo.xlsx> <nota.xml|pasta|notas.zip>...")
+				os.Exit(2)
+			}
+			conferirNFe(os.Args[2], os.Args[3:], cfg)
+			return
+		case "conciliar":
+			if len(os.Args) < 4 {
+				fmt.Println("Uso: ParserTrib conciliar <arquivo.xlsx> <efd.txt>")
+				os.Exit(2)
+			}
+			cfg.ArquivoEFD = os.Args[3]
+			os.Exit(validar(os.Args[2], cfg))
+		case "modelo":
+			destino := "modelo_produtos.xlsx"
+			if len(os.Args) > 2 {
+				destino = os.Args[2]
+			}
+			gerarModelo(destino, cfg)
+			return
+		}
 	}
 
 	// Modo CLI (comportamento original)
@@ -53,21 +144,35 @@ func main() {
 	}
 }
 
-// processar executa a validação completa com 4 etapas
-func processar(caminho string, cfg *config.Config) {
+// validar executa a validação e devolve o código de saída: 0 sem erros, 1 com problemas de
+// severidade erro (avisos e informativos não reprovam) e 2 quando a planilha não pôde ser validada
+func validar(caminho string, cfg *config.Config) int {
+	resultado := processar(caminho, cfg)
+	if resultado == nil {
+		return 2
+	}
+	if resultado.TotalErros() > 0 {
+		return 1
+	}
+	return 0
+}
+
+// processar executa a validação completa com 4 etapas e devolve o resultado (nil se a planilha
+// não pôde ser lida)
+func processar(caminho string, cfg *config.Config) *domain.ResultadoValidacaoCompleto {
 	fmt.Println("\n🔄 Iniciando processamento...")
 
 	reader, err := excel.NovoReader(caminho, cfg.SheetPadrao)
 	if err != nil {
 		fmt.Println("❌ Erro ao abrir arquivo:", err)
-		return
+		return nil
 	}
 	defer reader.Close()
 
 	planilha, err := reader.ObterMetadados()
 	if err != nil {
 		fmt.Println("❌ Erro ao ler metadados:", err)
-		return
+		return nil
 	}
 
 	fmt.Printf("\n✅ Planilha Carregada!\n")
@@ -88,38 +193,74 @@ func processar(caminho string, cfg *config.Config) {
 	rows, err := reader.ObterTodasLinhas()
 	if err != nil {
 		fmt.Println("❌ Erro ao ler linhas:", err)
-		return
+		return nil
+	}
+
+	metadados, err := reader.ObterMetadadosCelulas(rows)
+	if err != nil {
+		fmt.Println("❌ Erro ao ler formato das células:", err)
+		return nil
+	}
+
+	opcoes, err := carregarOpcoes(cfg)
+	if err != nil {
+		fmt.Println("❌", err)
+		return nil
+	}
+
+	supressoes, err := baseline.Carregar(cfg.Baseline)
+	if err != nil {
+		fmt.Println("❌", err)
+		return nil
+	}
+
+	if cfg.ArquivoEFD != "" {
+		opcoes.ItensEFD, err = sped.LerEFD(cfg.ArquivoEFD)
+		if err != nil {
+			fmt.Println("❌", err)
+			return nil
+		}
+		fmt.Printf("📑 SPED EFD: %s (%d registros 0200)\n", cfg.ArquivoEFD, len(opcoes.ItensEFD))
 	}
 
 	inicio := time.Now()
-	validador := excel.NovoValidator(
-		rows,
-		cfg.SheetPadrao,
-		planilha.Cabecalhos,
-	)
+	validador := excel.NovoValidatorComOpcoes(rows, cfg.SheetPadrao, planilha.Cabecalhos, opcoes).ComMetadados(metadados)
 	resultado := validador.ValidarTudo(planilha.TotalLinhas)
+	resultado.NomeArquivo = filepath.Base(caminho)
+	baseline.Aplicar(supressoes, &resultado)
 	duracao := time.Since(inicio)
 	resultado.TempoExecucao = duracao
 
-	if resultado.TotalErros() == 0 {
+	if caminhoResultado, err := logger.SalvarResultado(caminho, cfg.DiretorioLogs, resultado); err != nil {
+		fmt.Println("❌ Erro ao salvar resultado:", err)
+	} else {
+		fmt.Printf("💾 Resultado salvo em: %s\n", caminhoResultado)
+	}
+
+	formatadorErros := formatter.Novo()
+	fmt.Print(formatadorErros.FormatarPerfil(resultado.Perfil))
+	fmt.Print(formatadorErros.FormatarCobertura(resultado))
+	fmt.Print(formatadorErros.FormatarDuplicados(resultado.Duplicados))
+	fmt.Print(formatadorErros.FormatarAvisos(resultado.AvisosConsistencia))
+	fmt.Print(formatadorErros.FormatarIgnorados(resultado.Ignorados))
+
+	if resultado.TotalProblemas() == 0 && resultado.TotalErros() == 0 {
 		fmt.Println("\n" + formatarLinha("=", 60))
 		fmt.Println("✓ NENHUM ERRO ENCONTRADO!")
 		fmt.Println(formatarLinha("=", 60))
-		fmt.Printf("✓ Todas as células estão preenchidas\n")
+		fmt.Printf("✓ Todas as células obrigatórias estão preenchidas\n")
 		fmt.Printf("✓ Todos os NCMs estão no formato correto\n")
 		fmt.Printf("✓ Todos os CST Origem estão válidos\n")
 		fmt.Printf("✓ Todos os CSOSN estão válidos\n")
 		fmt.Printf("✓ Todos os Tipo Item estão válidos\n")
+		fmt.Printf("✓ Nenhum código armazenado como número\n")
 		fmt.Printf("\n⏱️  Tempo: %v\n", duracao)
-		return
+		return &resultado
 	}
 
-	formatadorErros := formatter.Novo()
-	formatadorErros.OrdenarErros(resultado.ErrosVazias)
-	formatadorErros.OrdenarErros(resultado.ErrosNCM)
-	formatadorErros.OrdenarErros(resultado.ErrosCSTOrigem)
-	formatadorErros.OrdenarErros(resultado.ErrosCSOSN)
-	formatadorErros.OrdenarErros(resultado.ErrosTipoItem)
+	for _, categoria := range resultado.Categorias() {
+		formatadorErros.OrdenarErros(categoria.Erros)
+	}
 
 	saidaFormatada := formatadorErros.FormatarSaida(resultado)
 	fmt.Print(saidaFormatada)
@@ -137,16 +278,379 @@ func processar(caminho string, cfg *config.Config) {
 	fmt.Println("📊 ESTATÍSTICAS FINAIS")
 	fmt.Println(formatarLinha("=", 60))
 	fmt.Printf("📝 Células verificadas: %d\n", totalCelulas)
-	fmt.Printf("❌ Células vazias: %d\n", len(resultado.ErrosVazias))
-	fmt.Printf("⚠️  Erros NCM: %d\n", len(resultado.ErrosNCM))
-	fmt.Printf("⚠️  Erros CST Origem: %d\n", len(resultado.ErrosCSTOrigem))
-	fmt.Printf("⚠️  Erros CSOSN: %d\n", len(resultado.ErrosCSOSN))
-	fmt.Printf("⚠️  Erros Tipo Item: %d\n", len(resultado.ErrosTipoItem))
+	for _, categoria := range resultado.Categorias() {
+		if categoria.Tipo == domain.TipoVazia {
+			fmt.Printf("❌ Células vazias: %d\n", len(categoria.Erros))
+			continue
+		}
+		fmt.Printf("⚠️  %s: %d\n", capitalizar(categoria.Rotulo), len(categoria.Erros))
+	}
 
-	fmt.Printf("🔢 Total de erros: %d\n", resultado.TotalErros())
+	preenchidas, exigidas, cobertura := resultado.CoberturaObrigatorias()
+	fmt.Printf("📋 Cobertura dos campos obrigatórios: %.1f%% (%d de %d células)\n", cobertura, preenchidas, exigidas)
+	fmt.Printf("🔁 Grupos duplicados: %d (%d divergentes)\n", len(resultado.Duplicados), resultado.GruposDivergentes())
+	fmt.Printf("💡 Avisos de consistência: %d\n", len(resultado.AvisosConsistencia))
+	fmt.Printf("🙈 Ignorados pelo baseline: %d\n", len(resultado.Ignorados))
+	porSeveridade := resultado.PorSeveridade()
+	fmt.Printf("🔢 Total de erros: %d (avisos: %d | informativos: %d)\n",
+		resultado.TotalErros(),
+		porSeveridade[domain.SeveridadeAviso],
+		porSeveridade[domain.SeveridadeInfo])
 	fmt.Printf("⏱️  Tempo total: %v\n", duracao)
 	fmt.Println(formatarLinha("=", 60))
 	fmt.Println()
+
+	return &resultado
+}
+
+// carregarIndiceNCM lê a tabela NCM configurada; sem ela a validação segue sem sugestões de NCM
+func carregarIndiceNCM(cfg *config.Config) *ncm.Indice {
+	indice, err := ncm.CarregarCSV(cfg.TabelaNCM)
+	if err != nil {
+		fmt.Println("ℹ️  Sugestões de NCM desativadas:", err)
+		return nil
+	}
+	fmt.Printf("📚 Tabela NCM carregada: %d códigos\n", indice.Total())
+	return indice
+}
+
+// carregarBeneficios lê a tabela cBenef quando há UF configurada (PARSERTRIB_UF); sem ela, a regra fica desativada
+func carregarBeneficios(cfg *config.Config) *cbenef.Tabela {
+	if cfg.UF == "" {
+		return nil
+	}
+	tabela, err := cbenef.CarregarCSV(cfg.TabelaCBenef)
+	if err != nil {
+		fmt.Println("ℹ️  Regra do cBenef desativada:", err)
+		return nil
+	}
+	if !tabela.Possui(cfg.UF) {
+		fmt.Printf("ℹ️  Regra do cBenef desativada: a tabela não tem códigos para %s (UFs: %s)\n", cfg.UF, strings.Join(tabela.UFs(), ", "))
+		return nil
+	}
+	fmt.Printf("📚 Tabela cBenef carregada: %d códigos (UF %s)\n", tabela.Total(), cfg.UF)
+	return tabela
+}
+
+// carregarServicos lê a lista de serviços da LC 116; sem ela, os itens de serviço têm só o formato
+// do código validado
+func carregarServicos(cfg *config.Config) []excel.CodigoTabela {
+	servicos, err := excel.CarregarTabelaServicos(cfg.TabelaServico)
+	if err != nil {
+		fmt.Println("ℹ️  Lista de serviços da LC 116 indisponível, validando só o formato:", err)
+		return nil
+	}
+	fmt.Printf("📚 Lista de serviços da LC 116 carregada: %d itens\n", len(servicos))
+	return servicos
+}
+
+// carregarProdutosANP lê a tabela de produtos da ANP; sem ela, o código ANP dos combustíveis tem
+// só o formato validado
+func carregarProdutosANP(cfg *config.Config) []excel.CodigoTabela {
+	produtos, err := excel.CarregarTabelaANP(cfg.TabelaANP)
+	if err != nil {
+		fmt.Println("ℹ️  Tabela de produtos da ANP indisponível, validando só o formato:", err)
+		return nil
+	}
+	fmt.Printf("📚 Tabela de produtos da ANP carregada: %d códigos\n", len(produtos))
+	return produtos
+}
+
+// carregarOpcoes carrega o perfil de regras e as tabelas usadas na validação de uma planilha.
+// Tabela opcional ausente só desativa a regra correspondente; perfil inválido ou tabela ilegível são erro.
+func carregarOpcoes(cfg *config.Config) (excel.OpcoesValidacao, error) {
+	opcoes := excel.OpcoesValidacao{
+		IndiceNCM:   carregarIndiceNCM(cfg),
+		ColunaChave: cfg.ColunaChave,
+		Beneficios:  carregarBeneficios(cfg),
+		UF:          cfg.UF,
+		Servicos:    carregarServicos(cfg),
+
+		PrefixosCombustivel: cfg.PrefixosCombustivel,
+		ANP:                 carregarProdutosANP(cfg),
+	}
+
+	perfil, err := excel.CarregarPerfilRegras(cfg.PerfilRegras)
+	if err != nil {
+		return opcoes, err
+	}
+	if perfil.Personalizado() {
+		fmt.Printf("🎚️  Perfil de regras: %s (%d severidades alteradas, %d regras opcionais habilitadas, %d colunas configuradas)\n",
+			cfg.PerfilRegras, len(perfil.Severidades), len(perfil.Habilitar), len(perfil.Colunas))
+	}
+	opcoes.Perfil = perfil
+
+	if opcoes.Unidades, err = excel.CarregarTabelaUnidades(cfg.TabelaUnidade); err != nil {
+		return opcoes, err
+	}
+	if opcoes.Reforma, err = carregarTabelaReforma(cfg, perfil); err != nil {
+		return opcoes, err
+	}
+	return opcoes, nil
+}
+
+// carregarTabelaReforma lê a tabela de CST IBS/CBS e cClassTrib só quando o perfil habilita as regras
+// da reforma tributária; nesse caso a tabela é obrigatória
+func carregarTabelaReforma(cfg *config.Config, perfil *excel.PerfilRegras) (*reforma.Tabela, error) {
+	if !perfil.ReformaHabilitada() {
+		return nil, nil
+	}
+	tabela, err := reforma.Carregar(cfg.TabelaReforma)
+	if err != nil {
+		return nil, fmt.Errorf("o perfil habilita as regras IBS/CBS, mas a tabela não pôde ser carregada: %w", err)
+	}
+	csts, classTribs := tabela.Total()
+	fmt.Printf("📚 Tabela IBS/CBS versão %s carregada: %d CSTs, %d cClassTrib\n", tabela.Versao, csts, classTribs)
+	return tabela, nil
+}
+
+// gerarBaseline cria o arquivo de supressões com todos os problemas de um resultado salvo
+func gerarBaseline(caminhoResultado, destino string) {
+	resultado, err := baseline.CarregarResultado(caminhoResultado)
+	if err != nil {
+		fmt.Println("❌", err)
+		os.Exit(1)
+	}
+
+	b := baseline.Gerar(resultado, caminhoResultado)
+	if err := baseline.Salvar(b, destino); err != nil {
+		fmt.Println("❌", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✅ Baseline salvo em: %s (%d problemas aceitos de '%s')\n", destino, len(b.Supressoes), resultado.NomeArquivo)
+}
+
+// comparar valida (ou lê) as duas versões e mostra os problemas corrigidos, novos e persistentes
+func comparar(caminhoAnterior, caminhoAtual string, cfg *config.Config) {
+	fmt.Printf("\n🔍 Comparando '%s' com '%s' (chave: %s)...\n", caminhoAnterior, caminhoAtual, cfg.ColunaChave)
+
+	opcoes, err := carregarOpcoes(cfg)
+	if err != nil {
+		fmt.Println("❌", err)
+		os.Exit(1)
+	}
+	supressoes, err := baseline.Carregar(cfg.Baseline)
+	if err != nil {
+		fmt.Println("❌", err)
+		os.Exit(1)
+	}
+
+	anterior, err := comparacao.Carregar(caminhoAnterior, cfg.SheetPadrao, opcoes, supressoes)
+	if err != nil {
+		fmt.Println("❌ Versão anterior:", err)
+		os.Exit(1)
+	}
+	atual, err := comparacao.Carregar(caminhoAtual, cfg.SheetPadrao, opcoes, supressoes)
+	if err != nil {
+		fmt.Println("❌ Versão atual:", err)
+		os.Exit(1)
+	}
+
+	resultado := comparacao.Comparar(anterior, atual)
+	fmt.Print(formatter.Novo().FormatarComparacao(resultado))
+
+	fmt.Println("\n" + formatarLinha("=", 60))
+	fmt.Println("📊 RESUMO DA COMPARAÇÃO")
+	fmt.Println(formatarLinha("=", 60))
+	fmt.Printf("✅ Corrigidos: %d\n", resultado.TotalCorrigidos)
+	fmt.Printf("🆕 Novos: %d\n", resultado.TotalNovos)
+	fmt.Printf("⏳ Persistentes: %d\n", resultado.TotalPersistentes)
+	fmt.Println(formatarLinha("=", 60))
+	fmt.Println()
+}
+
+// conferirNFe cruza a planilha com os itens das NF-e informadas (XMLs soltos, pastas ou .zip)
+func conferirNFe(caminho string, xmls []string, cfg *config.Config) {
+	fmt.Printf("\n🧾 Conferindo '%s' com as NF-e de %s...\n", caminho, strings.Join(xmls, ", "))
+
+	cabecalhos, rows, err := excel.LerLinhas(caminho, cfg.SheetPadrao)
+	if err != nil {
+		fmt.Println("❌", err)
+		os.Exit(1)
+	}
+	lote, err := nfe.Carregar(xmls...)
+	if err != nil {
+		fmt.Println("❌", err)
+		os.Exit(1)
+	}
+
+	resultado := nfe.Conferir(filepath.Base(caminho), cabecalhos, rows, lote)
+	fmt.Print(formatter.Novo().FormatarConferenciaNFe(resultado))
+
+	fmt.Println("\n" + formatarLinha("=", 60))
+	fmt.Println("📊 RESUMO DA CONFERÊNCIA COM NF-e")
+	fmt.Println(formatarLinha("=", 60))
+	fmt.Printf("🧾 XMLs lidos: %d (ignorados: %d)\n", resultado.TotalXMLs, len(resultado.XMLsIgnorados))
+	fmt.Printf("📦 Itens nas notas: %d\n", resultado.TotalItens)
+	fmt.Printf("🔗 Itens conferidos: %d\n", resultado.TotalConferidos)
+	fmt.Printf("❓ Sem correspondência na planilha: %d\n", len(resultado.SemCorrespondencia))
+	fmt.Printf("⚠️  Divergências: %d\n", resultado.TotalDivergencias)
+	fmt.Println(formatarLinha("=", 60))
+	fmt.Println()
+}
+
+// separar valida a planilha e grava <nome>_validas.xlsx e <nome>_rejeitadas.xlsx ao lado do original
+func separar(caminho string, cfg *config.Config) {
+	resultado := processar(caminho, cfg)
+	if resultado == nil {
+		return
+	}
+
+	extensao := filepath.Ext(caminho)
+	base := strings.TrimSuffix(caminho, extensao)
+	destinoValidas := base + "_validas" + extensao
+	destinoRejeitadas := base + "_rejeitadas" + extensao
+
+	fmt.Println("✂️  Separando linhas válidas e rejeitadas...")
+	separacao, err := excel.SepararLinhas(caminho, cfg.SheetPadrao, *resultado, destinoValidas, destinoRejeitadas)
+	if err != nil {
+		fmt.Println("❌", err)
+		return
+	}
+
+	fmt.Printf("✅ %d linhas válidas salvas em: %s\n", separacao.Validas, destinoValidas)
+	fmt.Printf("✅ %d linhas rejeitadas salvas em: %s\n", separacao.Rejeitadas, destinoRejeitadas)
+}
+
+// exportarSPED valida a planilha e, sem erros bloqueantes nem problemas de leiaute, grava os registros
+// 0190 e 0200 da EFD ICMS/IPI. Códigos de saída iguais aos de validar (0 ok, 1 recusado, 2 falha).
+func exportarSPED(caminho, destino string, cfg *config.Config) int {
+	resultado := processar(caminho, cfg)
+	if resultado == nil {
+		return 2
+	}
+	if bloqueantes := resultado.TotalErros(); bloqueantes > 0 {
+		fmt.Printf("⛔ Exportação SPED recusada: %d problema(s) com severidade erro na planilha\n", bloqueantes)
+		return 1
+	}
+
+	cabecalhos, rows, err := excel.LerLinhas(caminho, cfg.SheetPadrao)
+	if err != nil {
+		fmt.Println("❌", err)
+		return 2
+	}
+
+	fmt.Println("📄 Montando registros 0190/0200...")
+	exportacao, problemas := sped.Gerar(rows, cabecalhos)
+	if len(problemas) > 0 {
+		fmt.Println("\n" + formatarLinha("=", 60))
+		fmt.Printf("--- PROBLEMAS DE LEIAUTE SPED (%d) ---\n", len(problemas))
+		fmt.Println(formatarLinha("=", 60))
+		for _, problema := range problemas {
+			fmt.Println(problema.String())
+		}
+		fmt.Println("\n⛔ Exportação SPED recusada: corrija os campos acima")
+		return 1
+	}
+
+	arquivo, err := os.Create(destino)
+	if err != nil {
+		fmt.Println("❌ Erro ao criar arquivo SPED:", err)
+		return 2
+	}
+	defer arquivo.Close()
+
+	if err := exportacao.Escrever(arquivo); err != nil {
+		fmt.Println("❌", err)
+		return 2
+	}
+
+	fmt.Printf("✅ %d registro(s) 0190 e %d registro(s) 0200 salvos em: %s\n",
+		len(exportacao.Registros0190), len(exportacao.Registros0200), destino)
+	return 0
+}
+
+// corrigir aplica as correções automáticas, grava a planilha corrigida e revalida o resultado
+func corrigir(caminho string, cfg *config.Config) {
+	fmt.Println("\n🔧 Aplicando correções automáticas...")
+
+	reader, err := excel.NovoReader(caminho, cfg.SheetPadrao)
+	if err != nil {
+		fmt.Println("❌ Erro ao abrir arquivo:", err)
+		return
+	}
+	defer reader.Close()
+
+	planilha, err := reader.ObterMetadados()
+	if err != nil {
+		fmt.Println("❌ Erro ao ler metadados:", err)
+		return
+	}
+
+	rows, err := reader.ObterTodasLinhas()
+	if err != nil {
+		fmt.Println("❌ Erro ao ler linhas:", err)
+		return
+	}
+
+	metadados, err := reader.ObterMetadadosCelulas(rows)
+	if err != nil {
+		fmt.Println("❌ Erro ao ler formato das células:", err)
+		return
+	}
+
+	_, correcoes := excel.NovoCorretor(rows, planilha.Cabecalhos).ComMetadados(metadados).Corrigir()
+	if len(correcoes) == 0 {
+		fmt.Println("✓ Nenhuma correção automática aplicável")
+		processar(caminho, cfg)
+		return
+	}
+
+	destino := excel.CaminhoCorrigido(caminho)
+	if err := excel.SalvarCorrecoes(caminho, destino, cfg.SheetPadrao, correcoes); err != nil {
+		fmt.Println("❌ Erro ao salvar planilha corrigida:", err)
+		return
+	}
+
+	fmt.Println("\n" + formatarLinha("=", 60))
+	fmt.Printf("--- CÉLULAS CORRIGIDAS (%d) ---\n", len(correcoes))
+	fmt.Println(formatarLinha("=", 60))
+	for _, correcao := range correcoes {
+		fmt.Println(correcao.String())
+	}
+	fmt.Printf("\n✅ Planilha corrigida salva em: %s\n", destino)
+
+	caminhoLog, err := logger.SalvarLogCorrecoes(caminho, cfg.DiretorioLogs, destino, correcoes)
+	if err != nil {
+		fmt.Println("❌ Erro ao salvar log de correções:", err)
+	} else {
+		fmt.Printf("✅ Log de correções salvo em: %s\n", caminhoLog)
+	}
+
+	// Revalida o arquivo gravado — sobram apenas os erros que exigem análise manual
+	fmt.Println("\n🔁 Revalidando a planilha corrigida...")
+	processar(destino, cfg)
+}
+
+// gerarModelo grava a planilha modelo com listas suspensas e comentários nos cabeçalhos
+func gerarModelo(destino string, cfg *config.Config) {
+	perfil, err := excel.CarregarPerfilRegras(cfg.PerfilRegras)
+	if err != nil {
+		fmt.Println("❌", err)
+		return
+	}
+
+	modelo, err := excel.GerarModelo(cfg.SheetPadrao, perfil)
+	if err != nil {
+		fmt.Println("❌", err)
+		return
+	}
+	defer modelo.Close()
+
+	if err := modelo.SaveAs(destino); err != nil {
+		fmt.Println("❌ Erro ao salvar planilha modelo:", err)
+		return
+	}
+	fmt.Printf("✅ Planilha modelo salva em: %s\n", destino)
+}
+
+// capitalizar deixa a primeira letra maiúscula ("erros NCM" → "Erros NCM")
+func capitalizar(texto string) string {
+	if texto == "" {
+		return texto
+	}
+	return strings.ToUpper(texto[:1]) + texto[1:]
 }
 
 func formatarLinha(char string, tamanho int) string {